@@ -0,0 +1,190 @@
+package tiq
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// TagOptioner is implemented by schemas declaring the options allowed in each
+// tag they read (e.g. {"env": {"name", "default", "optional"}}). A nil list
+// allows any option. See CheckTags.
+type TagOptioner interface {
+	TagOptions() map[string][]string
+}
+
+// CheckTags checks the tags declared by the schema on every leaf field of the
+// given struct's type, including fields behind nil pointers, and reports unknown options, duplicated options, malformed
+// segments and malformed struct tags at once as Errors of *FieldError.
+//
+// Allowed options are declared by implementing TagOptioner. Schemas only
+// implementing TagKeyer get their tags checked for duplicated options and
+// malformed segments.
+func CheckTags[Schema any](value any) error {
+	options, err := tagOptions(reflect.TypeFor[Schema]())
+	if err != nil {
+		return err
+	}
+
+	inspector, err := Inspect(value)
+	if err != nil {
+		return err
+	}
+
+	keys := slices.Sorted(maps.Keys(options))
+	typ := inspector.value.Type()
+
+	errs := Errors{}
+	for path, meta := range leafFields(typ, "", map[reflect.Type]bool{typ: true}) {
+		if meta.tagsErr != nil {
+			errs = append(errs, newFieldError(typ, path, meta.tagsErr))
+		}
+
+		for _, key := range keys {
+			tag, ok := meta.field.Tag.Lookup(key)
+			if !ok {
+				continue
+			}
+
+			for _, err := range checkTag(key, tag, options[key]) {
+				e := newFieldError(typ, path, err)
+				e.TagKey = key
+
+				errs = append(errs, e)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// leafFields returns the path and metadata of every leaf field of the given
+// struct type, in the same order as Inspector.Walk. Unlike Walk, it follows
+// the type, so fields behind nil pointers are visited too.
+func leafFields(typ reflect.Type, prefix string, visiting map[reflect.Type]bool) iter.Seq2[string, *fieldInfo] {
+	return func(yield func(string, *fieldInfo) bool) {
+		info := typeInfoOf(typ)
+
+		for i := range info.fields {
+			meta := &info.fields[i]
+			path := prefix + meta.field.Name
+
+			if meta.inner == nil || visiting[meta.inner] {
+				if !yield(path, meta) {
+					return
+				}
+				continue
+			}
+
+			visiting[meta.inner] = true
+			more := true
+			for innerPath, innerMeta := range leafFields(meta.inner, path+".", visiting) {
+				if more = yield(innerPath, innerMeta); !more {
+					break
+				}
+			}
+			delete(visiting, meta.inner)
+
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// tagOptions returns the options allowed by the schema for each of its tags.
+func tagOptions(schema reflect.Type) (map[string][]string, error) {
+	switch s := reflect.New(schema).Interface().(type) {
+	case TagOptioner:
+		return s.TagOptions(), nil
+	case TagKeyer:
+		options := map[string][]string{}
+		for _, key := range s.TagKeys() {
+			options[key] = nil
+		}
+		return options, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s implements neither TagOptioner nor TagKeyer", ErrUndeclaredTags, schema)
+}
+
+// checkTag returns every problem found in the given tag's key-value list.
+func checkTag(key, tag string, allowed []string) []error {
+	entries, err := tokenize(tag)
+	if err != nil {
+		return []error{fmt.Errorf("%w %s: %w", ErrMalformedTag, key, err)}
+	}
+
+	errs := []error{}
+	seen := map[string]bool{}
+
+	for i, e := range entries {
+		switch {
+		case e.key == "" && e.value == "":
+			// an empty tag is fine, only empty segments of a list are malformed
+			if len(entries) > 1 {
+				errs = append(errs, fmt.Errorf("%w %s: empty segment %d", ErrMalformedTag, key, i))
+			}
+
+		case e.key == "":
+			errs = append(errs, fmt.Errorf("%w %s: segment %d has no key", ErrMalformedTag, key, i))
+
+		case allowed != nil && !slices.Contains(allowed, e.key):
+			if suggestion, ok := closest(e.key, allowed); ok {
+				errs = append(errs, fmt.Errorf("%w %q in %s tag, did you mean %q?", ErrUnknownOption, e.key, key, suggestion))
+			} else {
+				errs = append(errs, fmt.Errorf("%w %q in %s tag", ErrUnknownOption, e.key, key))
+			}
+
+		case seen[e.key]:
+			errs = append(errs, fmt.Errorf("%w %q in %s tag", ErrDuplicateOption, e.key, key))
+		}
+
+		seen[e.key] = true
+	}
+
+	return errs
+}
+
+// closest returns the candidate closest to s, if it is only a typo away.
+func closest(s string, candidates []string) (string, bool) {
+	best, bestDistance := "", 3 // more than 2 edits is not a typo
+	for _, candidate := range candidates {
+		if d := distance(s, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	return best, best != ""
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
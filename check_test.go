@@ -0,0 +1,145 @@
+package tiq
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type checkedSchema struct {
+	Name     string `tag:"env | get('name')"`
+	Optional bool   `tag:"env | has('optional')"`
+}
+
+func (checkedSchema) TagOptions() map[string][]string {
+	return map[string][]string{
+		"env":  {"name", "default", "optional"},
+		"json": nil,
+	}
+}
+
+func TestCheckTags(t *testing.T) {
+	t.Run("accepts valid tags", func(t *testing.T) {
+		type Config struct {
+			Port     int    `env:"name=PORT, default='8080', optional"`
+			Host     string `env:"name=HOST" json:"host"`
+			Untagged string
+			Empty    string `env:""`
+		}
+
+		assert.NoError(t, CheckTags[checkedSchema](&Config{}))
+	})
+
+	t.Run("reports every problem with its field", func(t *testing.T) {
+		type Database struct {
+			Host string `env:"name=DB_HOST, name=HOST"`
+		}
+		type Config struct {
+			Port     int    `env:"name=PORT, optinal"`
+			Timeout  int    `env:"name=TIMEOUT,, =5s"`
+			Secret   string `env:"name=SECRET, default='abc"`
+			Database Database
+			Extra    string `env:"name=EXTRA, extra" json:"extra,extra"`
+		}
+
+		err := CheckTags[checkedSchema](Config{})
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 7)
+
+		messages := []string{}
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		assert.Equal(t, []string{
+			`tiq.Config.Port: unknown tag option "optinal" in env tag, did you mean "optional"?`,
+			`tiq.Config.Timeout: malformed tag env: empty segment 1`,
+			`tiq.Config.Timeout: malformed tag env: segment 2 has no key`,
+			`tiq.Config.Secret: malformed tag env: unterminated quote at offset 21`,
+			`tiq.Config.Database.Host: duplicated tag option "name" in env tag`,
+			`tiq.Config.Extra: unknown tag option "extra" in env tag`,
+			`tiq.Config.Extra: duplicated tag option "extra" in json tag`,
+		}, messages)
+
+		assert.ErrorIs(t, err, ErrUnknownOption)
+		assert.ErrorIs(t, err, ErrDuplicateOption)
+		assert.ErrorIs(t, err, ErrMalformedTag)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, errs[0], &fieldErr)
+		assert.Equal(t, "Port", fieldErr.FieldPath)
+		assert.Equal(t, "env", fieldErr.TagKey)
+	})
+
+	t.Run("checks fields behind nil pointers", func(t *testing.T) {
+		type Database struct {
+			Host string `env:"name=DB_HOST, optinal"`
+		}
+		type Node struct {
+			Name string `env:"name=NAME, optinal"`
+			Next *Node
+		}
+		type Config struct {
+			Name     string `env:"name=NAME, optinal"`
+			Database *Database
+			Node     *Node
+		}
+
+		for _, value := range []any{Config{}, &Config{}} {
+			err := CheckTags[checkedSchema](value)
+
+			var errs Errors
+			assert.ErrorAs(t, err, &errs)
+
+			paths := []string{}
+			for _, err := range errs {
+				var fieldErr *FieldError
+				assert.ErrorAs(t, err, &fieldErr)
+				paths = append(paths, fieldErr.FieldPath)
+			}
+			assert.Equal(t, []string{"Name", "Database.Host", "Node.Name"}, paths)
+		}
+	})
+
+	t.Run("reports malformed struct tags", func(t *testing.T) {
+		// built at runtime, since go vet rejects malformed tags
+		typ := reflect.StructOf([]reflect.StructField{
+			{Name: "Port", Type: reflect.TypeFor[int](), Tag: `env:"name=PORT" json:port`},
+		})
+
+		err := CheckTags[checkedSchema](reflect.New(typ).Interface())
+		assert.ErrorIs(t, err, ErrMalformedTag)
+		assert.ErrorContains(t, err, `.Port: malformed tag: key "json" is not followed by a quoted value at offset 16`)
+	})
+
+	t.Run("only checks structure of tags declared with TagKeys", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"name=PORT, anything, name=P"`
+		}
+
+		err := CheckTags[keyedSchema](Config{})
+		assert.ErrorIs(t, err, ErrDuplicateOption)
+		assert.NotErrorIs(t, err, ErrUnknownOption)
+	})
+
+	t.Run("returns error when the schema does not declare its tags", func(t *testing.T) {
+		type Schema struct{}
+
+		err := CheckTags[Schema](struct{}{})
+		assert.ErrorIs(t, err, ErrUndeclaredTags)
+	})
+
+	t.Run("returns error when value is not a struct", func(t *testing.T) {
+		err := CheckTags[checkedSchema]("abc")
+		assert.ErrorIs(t, err, ErrNotAStruct)
+	})
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, 0, distance("optional", "optional"))
+	assert.Equal(t, 1, distance("optinal", "optional"))
+	assert.Equal(t, 2, distance("defualt", "default"))
+	assert.Equal(t, 4, distance("name", ""))
+}
@@ -0,0 +1,174 @@
+package tiq
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Entry is a key-value pair of a comma-separated key-value list, as returned
+// by the DSL's entries() function. Value is empty for entries without a value
+// (e.g. `optional` in "name=URL, optional").
+type Entry struct {
+	Key   string `expr:"key"`
+	Value string `expr:"value"`
+}
+
+// listValue returns the given list as a reflect.Value, if it is a slice or an array.
+func listValue(name string, list any) (reflect.Value, error) {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return reflect.Value{}, fmt.Errorf("%s() first argument must be a list, got %T", name, list)
+	}
+
+	return v, nil
+}
+
+func fnIncludes(args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("includes() requires exactly 2 arguments")
+	}
+
+	if args[0] == nil {
+		return false, nil
+	}
+
+	list, err := listValue("includes", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list.Len() {
+		if reflect.DeepEqual(list.Index(i).Interface(), args[1]) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func fnLen(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("len() requires exactly 1 argument")
+	}
+
+	if args[0] == nil {
+		return 0, nil
+	}
+
+	v := reflect.ValueOf(args[0])
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len(), nil
+	}
+
+	return nil, fmt.Errorf("len() argument must be a string, list or map, got %T", args[0])
+}
+
+func fnUnique(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("unique() requires exactly 1 argument")
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	list, err := listValue("unique", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	unique := reflect.MakeSlice(reflect.SliceOf(list.Type().Elem()), 0, list.Len())
+	seen := map[any]bool{}
+
+	for i := range list.Len() {
+		item := list.Index(i)
+		if !item.Comparable() {
+			return nil, fmt.Errorf("unique() cannot compare %s", item.Type())
+		}
+
+		key := item.Interface()
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		unique = reflect.Append(unique, item)
+	}
+
+	return unique.Interface(), nil
+}
+
+func fnKeys(args ...any) (any, error) {
+	entries, err := listEntries("keys", args)
+	if entries == nil || err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+
+	return keys, nil
+}
+
+func fnEntries(args ...any) (any, error) {
+	entries, err := listEntries("entries", args)
+	if entries == nil || err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func fnKvmap(args ...any) (any, error) {
+	entries, err := listEntries("kvmap", args)
+	if entries == nil || err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string, len(entries))
+	for _, e := range entries {
+		// the first occurrence wins, like get() and reflect.StructTag.Lookup
+		if _, ok := m[e.Key]; !ok {
+			m[e.Key] = e.Value
+		}
+	}
+
+	return m, nil
+}
+
+// listEntries tokenizes the key-value list given as the only argument of the
+// named function. It returns nil without error for a missing tag.
+func listEntries(name string, args []any) ([]Entry, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%s() requires exactly 1 argument", name)
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s() argument must be a string", name)
+	}
+
+	tokens, err := tokenize(str)
+	if err != nil {
+		return nil, fmt.Errorf("%s() %w", name, err)
+	}
+
+	entries := make([]Entry, 0, len(tokens))
+	for _, t := range tokens {
+		if t.key == "" && t.value == "" {
+			continue
+		}
+
+		entries = append(entries, Entry{Key: t.key, Value: t.value})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,212 @@
+package tiq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFnIncludes(t *testing.T) {
+	t.Run("returns true when the list contains the value", func(t *testing.T) {
+		result, err := fnIncludes([]string{"a", "b"}, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, true, result)
+
+		result, err = fnIncludes([]any{1, 2}, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, true, result)
+	})
+
+	t.Run("returns false when the list doesn't contain the value", func(t *testing.T) {
+		result, err := fnIncludes([]string{"a", "b"}, "c")
+		assert.NoError(t, err)
+		assert.Equal(t, false, result)
+	})
+
+	t.Run("returns false for missing tag", func(t *testing.T) {
+		result, err := fnIncludes(nil, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, false, result)
+	})
+
+	t.Run("returns error when first argument is not a list", func(t *testing.T) {
+		_, err := fnIncludes("abc", "a")
+		assert.EqualError(t, err, "includes() first argument must be a list, got string")
+	})
+}
+
+func TestFnLen(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		len   int
+	}{
+		{"string", "abc", 3},
+		{"list", []string{"a", "b"}, 2},
+		{"map", map[string]any{"a": 1}, 1},
+		{"nil", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fnLen(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.len, result)
+		})
+	}
+
+	t.Run("returns error for other types", func(t *testing.T) {
+		_, err := fnLen(1)
+		assert.EqualError(t, err, "len() argument must be a string, list or map, got int")
+	})
+}
+
+func TestFnUnique(t *testing.T) {
+	t.Run("removes duplicates and keeps order", func(t *testing.T) {
+		result, err := fnUnique([]string{"b", "a", "b", "c", "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b", "a", "c"}, result)
+
+		result, err = fnUnique([]any{1, "1", 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []any{1, "1"}, result)
+	})
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		result, err := fnUnique(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns error for uncomparable items", func(t *testing.T) {
+		_, err := fnUnique([][]string{{"a"}})
+		assert.EqualError(t, err, "unique() cannot compare []string")
+	})
+}
+
+func TestFnKeys(t *testing.T) {
+	t.Run("returns every key", func(t *testing.T) {
+		result, err := fnKeys("name=URL, optional, default='a,b'")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "optional", "default"}, result)
+	})
+
+	t.Run("returns no keys for an empty list", func(t *testing.T) {
+		result, err := fnKeys("")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{}, result)
+	})
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		result, err := fnKeys(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns error when argument is not a string", func(t *testing.T) {
+		_, err := fnKeys(1)
+		assert.EqualError(t, err, "keys() argument must be a string")
+	})
+}
+
+func TestFnEntries(t *testing.T) {
+	t.Run("returns every entry in order", func(t *testing.T) {
+		result, err := fnEntries("name=URL, optional, default='a,b'")
+		assert.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{Key: "name", Value: "URL"},
+			{Key: "optional"},
+			{Key: "default", Value: "a,b"},
+		}, result)
+	})
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		result, err := fnEntries(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns tokenizer errors", func(t *testing.T) {
+		_, err := fnEntries("default='a")
+		assert.ErrorContains(t, err, "entries() unterminated quote")
+	})
+}
+
+func TestFnKvmap(t *testing.T) {
+	t.Run("returns every entry as a map", func(t *testing.T) {
+		result, err := fnKvmap("name=URL, optional, default='a,b'")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"name": "URL", "optional": "", "default": "a,b"}, result)
+	})
+
+	t.Run("keeps the first value of duplicated keys", func(t *testing.T) {
+		result, err := fnKvmap("a=1, a=2")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1"}, result)
+	})
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		result, err := fnKvmap(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns error when argument is not a string", func(t *testing.T) {
+		_, err := fnKvmap(1)
+		assert.EqualError(t, err, "kvmap() argument must be a string")
+	})
+}
+
+func TestCollectionFunctions_Expressions(t *testing.T) {
+	t.Run("operates on lists and entries", func(t *testing.T) {
+		type Schema struct {
+			Oneof    []string `tag:"validate | get('oneof') | split('|') | unique()"`
+			HasB     bool     `tag:"validate | get('oneof') | split('|') | includes('b')"`
+			Count    int      `tag:"validate | keys() | len()"`
+			Flags    []string `tag:"map(filter(entries(validate), #.value == ''), #.key)"`
+			Settings []string `tag:"map(filter(entries(validate), #.value != ''), #.key + ':' + #.value)"`
+		}
+
+		schema, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{
+			"validate": "required, oneof=a|b|a, min=1, omitempty",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{
+			Oneof:    []string{"a", "b"},
+			HasB:     true,
+			Count:    4,
+			Flags:    []string{"required", "omitempty"},
+			Settings: []string{"oneof:a|b|a", "min:1"},
+		}, schema)
+	})
+
+	t.Run("supports the in operator", func(t *testing.T) {
+		type Schema struct {
+			HasB bool `tag:"'b' in split(validate, '|')"`
+		}
+
+		schema, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{"validate": "a|b"})
+		assert.NoError(t, err)
+		assert.True(t, schema.HasB)
+	})
+
+	t.Run("assigns every key-value pair to map fields", func(t *testing.T) {
+		type Schema struct {
+			Options map[string]string `tag:"kvmap(validate)"`
+			Any     map[string]any    `tag:"kvmap(validate)"`
+			Ints    map[string]int    `tag:"{'min': validate | get('min')}"`
+			Ordered []Entry           `tag:"entries(validate)"`
+			FromMap map[string]string `tag:"entries(validate)"`
+		}
+
+		schema, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{"validate": "required, min=1"})
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{
+			Options: map[string]string{"required": "", "min": "1"},
+			Any:     map[string]any{"required": "", "min": "1"},
+			Ints:    map[string]int{"min": 1},
+			Ordered: []Entry{{Key: "required"}, {Key: "min", Value: "1"}},
+			FromMap: map[string]string{"required": "", "min": "1"},
+		}, schema)
+	})
+}
@@ -0,0 +1,187 @@
+package tiq
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/AnatoleLucet/as"
+)
+
+// convert converts the value to the given type using as.Type, and
+// additionally decodes maps into structs and slices of maps into slices of structs.
+// Maps and lists of Entry are decoded into maps, converting each key and value.
+func convert(typ reflect.Type, value any) (reflect.Value, error) {
+	switch typ.Kind() {
+	case reflect.Pointer:
+		elem, err := convert(typ.Elem(), value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+
+	case reflect.Struct:
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Map {
+			return decodeStruct(typ, v)
+		}
+
+	case reflect.Map:
+		if entries, ok := value.([]Entry); ok {
+			return decodeEntries(typ, entries)
+		}
+
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Map {
+			return decodeMap(typ, v)
+		}
+
+	case reflect.Slice:
+		v := reflect.ValueOf(value)
+		if isStructType(typ.Elem()) && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) {
+			return decodeSlice(typ, v)
+		}
+	}
+
+	v, err := as.Type(typ, value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.CanConvert(typ) {
+		return reflect.Value{}, fmt.Errorf("cannot convert %T to %s", value, typ)
+	}
+
+	return rv.Convert(typ), nil
+}
+
+// decodeStruct decodes a map into a struct, matching keys to field names case-insensitively.
+func decodeStruct(typ reflect.Type, m reflect.Value) (reflect.Value, error) {
+	if m.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, fmt.Errorf("cannot decode %s into %s: keys must be strings", m.Type(), typ)
+	}
+
+	result := reflect.New(typ).Elem()
+
+	iter := m.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+
+		field, ok := typ.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, key)
+		})
+		if !ok || !field.IsExported() || !allocatable(typ, field.Index) {
+			return reflect.Value{}, fmt.Errorf("cannot decode key %q: no such field in %s", key, typ)
+		}
+
+		value := iter.Value().Interface()
+		if value == nil {
+			continue
+		}
+
+		v, err := convert(field.Type, value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot decode key %q: %w", key, err)
+		}
+
+		fieldByIndex(result, field.Index).Set(v)
+	}
+
+	return result, nil
+}
+
+// allocatable reports whether the field at the given index of typ can be set
+// on a new value, i.e. it isn't promoted through an unexported embedded pointer
+// that cannot be allocated.
+func allocatable(typ reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		field := typ.Field(x)
+		if field.Type.Kind() == reflect.Pointer && !field.IsExported() {
+			return false
+		}
+
+		typ = field.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+	}
+
+	return true
+}
+
+// decodeSlice decodes every element of a slice or array into a slice of the given type.
+func decodeSlice(typ reflect.Type, v reflect.Value) (reflect.Value, error) {
+	result := reflect.MakeSlice(typ, 0, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		elem, err := convert(typ.Elem(), v.Index(i).Interface())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot decode element %d: %w", i, err)
+		}
+
+		result = reflect.Append(result, elem)
+	}
+
+	return result, nil
+}
+
+// decodeMap decodes a map into a map of the given type, converting every key and value.
+func decodeMap(typ reflect.Type, m reflect.Value) (reflect.Value, error) {
+	result := reflect.MakeMapWithSize(typ, m.Len())
+
+	iter := m.MapRange()
+	for iter.Next() {
+		key, err := convert(typ.Key(), iter.Key().Interface())
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot decode key %v: %w", iter.Key(), err)
+		}
+
+		value := reflect.Zero(typ.Elem())
+		if v := iter.Value().Interface(); v != nil {
+			value, err = convert(typ.Elem(), v)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("cannot decode key %v: %w", iter.Key(), err)
+			}
+		}
+
+		result.SetMapIndex(key, value)
+	}
+
+	return result, nil
+}
+
+// decodeEntries decodes entries into a map of the given type. Like kvmap(),
+// the first entry of a repeated key wins.
+func decodeEntries(typ reflect.Type, entries []Entry) (reflect.Value, error) {
+	result := reflect.MakeMapWithSize(typ, len(entries))
+
+	for _, e := range entries {
+		key, err := convert(typ.Key(), e.Key)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot decode key %q: %w", e.Key, err)
+		}
+
+		value, err := convert(typ.Elem(), e.Value)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot decode key %q: %w", e.Key, err)
+		}
+
+		if !result.MapIndex(key).IsValid() {
+			result.SetMapIndex(key, value)
+		}
+	}
+
+	return result, nil
+}
+
+func isStructType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct
+}
@@ -0,0 +1,159 @@
+package tiq
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	type Range struct {
+		Min int
+		Max int
+	}
+
+	t.Run("converts scalars", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[int](), "42")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v.Interface())
+	})
+
+	t.Run("converts to pointers", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[*int](), "42")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, *v.Interface().(*int))
+	})
+
+	t.Run("decodes maps into structs", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[Range](), map[string]any{"min": "1", "MAX": 10})
+		assert.NoError(t, err)
+		assert.Equal(t, Range{Min: 1, Max: 10}, v.Interface())
+	})
+
+	t.Run("decodes maps into pointers to structs", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[*Range](), map[string]string{"max": "5"})
+		assert.NoError(t, err)
+		assert.Equal(t, &Range{Max: 5}, v.Interface())
+	})
+
+	t.Run("skips nil values", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[Range](), map[string]any{"min": nil, "max": 3})
+		assert.NoError(t, err)
+		assert.Equal(t, Range{Max: 3}, v.Interface())
+	})
+
+	t.Run("decodes nested structs", func(t *testing.T) {
+		type Rule struct {
+			Name  string
+			Range *Range
+		}
+
+		v, err := convert(reflect.TypeFor[Rule](), map[string]any{"name": "len", "range": map[string]any{"min": 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, Rule{Name: "len", Range: &Range{Min: 1}}, v.Interface())
+	})
+
+	t.Run("allocates embedded pointers", func(t *testing.T) {
+		type Embedded struct {
+			X int
+		}
+		type WithEmbedded struct {
+			*Embedded
+		}
+
+		v, err := convert(reflect.TypeFor[WithEmbedded](), map[string]any{"x": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, WithEmbedded{&Embedded{X: 1}}, v.Interface())
+	})
+
+	t.Run("returns error for fields promoted through unexported embedded pointers", func(t *testing.T) {
+		type embedded struct {
+			X int
+		}
+		type WithEmbedded struct {
+			*embedded
+		}
+
+		v, err := convert(reflect.TypeFor[WithEmbedded](), map[string]any{"x": 1})
+		assert.ErrorContains(t, err, `cannot decode key "x": no such field`)
+		assert.False(t, v.IsValid())
+	})
+
+	t.Run("decodes slices of maps into slices of structs", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[[]Range](), []any{map[string]any{"min": 1}, map[string]any{"max": 2}})
+		assert.NoError(t, err)
+		assert.Equal(t, []Range{{Min: 1}, {Max: 2}}, v.Interface())
+
+		v, err = convert(reflect.TypeFor[[]*Range](), []map[string]any{{"min": 1}})
+		assert.NoError(t, err)
+		assert.Equal(t, []*Range{{Min: 1}}, v.Interface())
+	})
+
+	t.Run("returns error for unknown keys", func(t *testing.T) {
+		_, err := convert(reflect.TypeFor[Range](), map[string]any{"avg": 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `cannot decode key "avg": no such field in tiq.Range`)
+	})
+
+	t.Run("returns error for invalid values", func(t *testing.T) {
+		_, err := convert(reflect.TypeFor[[]Range](), []any{map[string]any{"min": "x"}})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `cannot decode element 0: cannot decode key "min"`)
+	})
+
+	t.Run("returns error for non-string keys", func(t *testing.T) {
+		_, err := convert(reflect.TypeFor[Range](), map[int]any{1: 1})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "keys must be strings")
+	})
+
+	t.Run("converts maps into maps", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[map[string]string](), map[string]any{"a": 1, "b": "2", "c": nil})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2", "c": ""}, v.Interface())
+
+		v, err = convert(reflect.TypeFor[map[string]any](), map[string]string{"a": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "1"}, v.Interface())
+
+		v, err = convert(reflect.TypeFor[map[string]int](), map[string]any{"a": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1}, v.Interface())
+	})
+
+	t.Run("converts entries into maps", func(t *testing.T) {
+		v, err := convert(reflect.TypeFor[map[string]string](), []Entry{{Key: "a", Value: "1"}, {Key: "b"}, {Key: "a", Value: "2"}})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1", "b": ""}, v.Interface())
+	})
+
+	t.Run("returns error when map values cannot be converted", func(t *testing.T) {
+		_, err := convert(reflect.TypeFor[map[string]int](), map[string]any{"a": "abc"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `cannot decode key a`)
+	})
+}
+
+func TestField_SetFromMap(t *testing.T) {
+	type Range struct {
+		Min, Max int
+	}
+	type TestStruct struct {
+		Range Range
+	}
+
+	testStruct := TestStruct{}
+	inspector, err := Inspect(&testStruct)
+	assert.NoError(t, err)
+
+	field, ok := inspector.Field("Range")
+	assert.True(t, ok)
+
+	err = field.SetFrom(map[string]any{"min": 1, "max": "2"})
+	assert.NoError(t, err)
+	assert.Equal(t, Range{1, 2}, testStruct.Range)
+
+	err = field.SetFrom(map[string]any{"avg": 1})
+	assert.ErrorIs(t, err, ErrCannotConvert)
+}
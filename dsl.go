@@ -4,57 +4,88 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/parser"
+	"github.com/expr-lang/expr/types"
 	"github.com/expr-lang/expr/vm"
 )
 
 func parseTags[Schema any](tags map[string]string) (*Schema, error) {
-	tag := new(Schema)
-	inspector, err := Inspect(tag)
-	if err != nil {
-		return nil, err
-	}
+	return NewParser[Schema]().ParseTags(tags)
+}
 
-	for _, f := range inspector.Fields() {
-		expression, ok := f.Tag("tag")
-		if !ok {
-			continue
-		}
+// builtins is tiq's DSL function set.
+var builtins = map[string]function{
+	"get":     {fnGet, []any{new(func(string, string) (string, error))}},
+	"first":   {fnFirst, []any{new(func(string) (string, error))}},
+	"last":    {fnLast, []any{new(func(string) (string, error))}},
+	"nth":     {fnNth, []any{new(func(string, int) (string, error))}},
+	"has":     {fnHas, []any{new(func(string, string) (bool, error))}},
+	"split":   {fnSplit, []any{new(func(string, string) ([]string, error))}},
+	"default": {fnDefault, []any{new(func(any, any) (any, error))}},
+
+	"name":    {fnName, []any{new(func(string) (string, error))}},
+	"option":  {fnOption, []any{new(func(string, string) (bool, error))}},
+	"options": {fnOptions, []any{new(func(string) ([]string, error))}},
+
+	"upper":          {fnUpper, []any{new(func(string) (string, error))}},
+	"lower":          {fnLower, []any{new(func(string) (string, error))}},
+	"trim":           {fnTrim, []any{new(func(string) (string, error)), new(func(string, string) (string, error))}},
+	"replace":        {fnReplace, []any{new(func(string, string, string) (string, error))}},
+	"hasPrefix":      {fnHasPrefix, []any{new(func(string, string) (bool, error))}},
+	"hasSuffix":      {fnHasSuffix, []any{new(func(string, string) (bool, error))}},
+	"trimPrefix":     {fnTrimPrefix, []any{new(func(string, string) (string, error))}},
+	"trimSuffix":     {fnTrimSuffix, []any{new(func(string, string) (string, error))}},
+	"join":           {fnJoin, []any{new(func([]string, string) (string, error)), new(func([]any, string) (string, error))}},
+	"snake":          {fnSnake, []any{new(func(string) (string, error))}},
+	"camel":          {fnCamel, []any{new(func(string) (string, error))}},
+	"kebab":          {fnKebab, []any{new(func(string) (string, error))}},
+	"screamingSnake": {fnScreamingSnake, []any{new(func(string) (string, error))}},
+
+	"int":      {fnInt, []any{new(func(any) (int, error))}},
+	"float":    {fnFloat, []any{new(func(any) (float64, error))}},
+	"bool":     {fnBool, []any{new(func(any) (bool, error))}},
+	"duration": {fnDuration, []any{new(func(any) (time.Duration, error))}},
+	"time":     {fnTime, []any{new(func(any) (time.Time, error)), new(func(any, string) (time.Time, error))}},
+	"bytes":    {fnBytes, []any{new(func(any) (int64, error))}},
+
+	"includes": {fnIncludes, []any{new(func(any, any) (bool, error))}},
+	"len":      {fnLen, []any{new(func(any) (int, error))}},
+	"unique":   {fnUnique, []any{new(func(any) (any, error))}},
+	"keys":     {fnKeys, []any{new(func(string) ([]string, error))}},
+	"entries":  {fnEntries, []any{new(func(string) ([]Entry, error))}},
+	"kvmap":    {fnKvmap, []any{new(func(string) (map[string]string, error))}},
+}
 
-		program, err := compile(expression)
-		if err != nil {
-			return nil, err
-		}
+// compile compiles the expression with the builtins and the given functions.
+// If env is not nil, it declares the type of every variable, and the
+// expression is type checked against it.
+func compile(expression string, env map[string]any, functions ...*Functions) (*vm.Program, error) {
+	opts := []expr.Option{
+		expr.DisableAllBuiltins(),
+		expr.AsAny(),
+	}
 
-		output, err := expr.Run(program, tags)
-		if err != nil || output == nil {
-			continue
-		}
+	for name, fn := range builtins {
+		opts = append(opts, expr.Function(name, fn.fn, fn.types...))
+	}
 
-		err = f.SetFrom(output)
+	seen := map[string]bool{}
+	for _, fns := range append([]*Functions{globalFunctions}, functions...) {
+		fnOpts, err := fns.options(seen)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: failed to compile expression %q: %w", ErrCompileTag, expression, err)
 		}
-	}
-
-	return tag, nil
-}
 
-func compile(expression string) (*vm.Program, error) {
-	opts := []expr.Option{
-		expr.AllowUndefinedVariables(),
-
-		expr.DisableAllBuiltins(),
-		expr.Function("get", fnGet, new(func(string, string) (string, error))),
-		expr.Function("first", fnFirst, new(func(string) (string, error))),
-		expr.Function("last", fnLast, new(func(string) (string, error))),
-		expr.Function("nth", fnNth, new(func(string, int) (string, error))),
-		expr.Function("has", fnHas, new(func(string, string) (bool, error))),
-		expr.Function("split", fnSplit, new(func(string, string) ([]string, error))),
-		expr.Function("default", fnDefault, new(func(any, any) (any, error))),
+		opts = append(opts, fnOpts...)
+	}
 
-		expr.AsAny(),
+	if env != nil {
+		opts = append(opts, expr.Env(env))
+	} else {
+		opts = append(opts, expr.Env(shadowedTags(expression, seen)), expr.AllowUndefinedVariables())
 	}
 
 	program, err := expr.Compile(expression, opts...)
@@ -65,13 +96,25 @@ func compile(expression string) (*vm.Program, error) {
 	return program, nil
 }
 
-func kv(pair string) (string, string) {
-	parts := strings.SplitN(pair, "=", 2)
-	if len(parts) == 2 {
-		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+// shadowedTags declares the tags of an untyped expression named like a
+// function (e.g. `name`), so they resolve to the tag instead of the function
+// when used as a value, like in `get(name, 'x')`. Functions are only resolved
+// when called.
+func shadowedTags(expression string, functions map[string]bool) types.Map {
+	tags := types.Map{}
+
+	tree, err := parser.Parse(expression)
+	if err != nil {
+		return tags // reported by expr.Compile
+	}
+
+	for _, name := range identifiers(tree.Node) {
+		if _, ok := builtins[name]; ok || functions[name] {
+			tags[name] = types.Any
+		}
 	}
 
-	return strings.TrimSpace(parts[0]), ""
+	return tags
 }
 
 func fnGet(args ...any) (any, error) {
@@ -79,6 +122,11 @@ func fnGet(args ...any) (any, error) {
 		return nil, errors.New("get() requires exactly 2 arguments")
 	}
 
+	// a missing tag has nothing to look up
+	if args[0] == nil {
+		return nil, nil
+	}
+
 	str, ok := args[0].(string)
 	if !ok {
 		return nil, errors.New("get() first argument must be a string")
@@ -89,10 +137,14 @@ func fnGet(args ...any) (any, error) {
 		return nil, errors.New("get() second argument must be a string")
 	}
 
-	for pair := range strings.SplitSeq(str, ",") {
-		k, v := kv(pair)
-		if k == key {
-			return v, nil
+	entries, err := tokenize(str)
+	if err != nil {
+		return nil, fmt.Errorf("get() %w", err)
+	}
+
+	for _, e := range entries {
+		if e.key == key {
+			return e.value, nil
 		}
 	}
 
@@ -104,22 +156,21 @@ func fnFirst(args ...any) (any, error) {
 		return nil, errors.New("first() requires exactly 1 argument")
 	}
 
+	if args[0] == nil {
+		return nil, nil
+	}
+
 	str, ok := args[0].(string)
 	if !ok {
 		return nil, errors.New("first() argument must be a string")
 	}
 
-	pairs := strings.Split(str, ",")
-	if len(pairs) == 0 {
-		return nil, nil
-	}
-
-	k, v := kv(pairs[0])
-	if v == "" {
-		return k, nil
+	entries, err := tokenize(str)
+	if err != nil {
+		return nil, fmt.Errorf("first() %w", err)
 	}
 
-	return v, nil
+	return entries[0].content(), nil
 }
 
 func fnLast(args ...any) (any, error) {
@@ -127,22 +178,21 @@ func fnLast(args ...any) (any, error) {
 		return nil, errors.New("last() requires exactly 1 argument")
 	}
 
+	if args[0] == nil {
+		return nil, nil
+	}
+
 	str, ok := args[0].(string)
 	if !ok {
 		return nil, errors.New("last() argument must be a string")
 	}
 
-	pairs := strings.Split(str, ",")
-	if len(pairs) == 0 {
-		return nil, nil
-	}
-
-	k, v := kv(pairs[len(pairs)-1])
-	if v == "" {
-		return k, nil
+	entries, err := tokenize(str)
+	if err != nil {
+		return nil, fmt.Errorf("last() %w", err)
 	}
 
-	return v, nil
+	return entries[len(entries)-1].content(), nil
 }
 
 func fnNth(args ...any) (any, error) {
@@ -150,6 +200,10 @@ func fnNth(args ...any) (any, error) {
 		return nil, errors.New("nth() requires exactly 2 arguments")
 	}
 
+	if args[0] == nil {
+		return nil, nil
+	}
+
 	str, ok := args[0].(string)
 	if !ok {
 		return nil, errors.New("nth() first argument must be a string")
@@ -160,17 +214,16 @@ func fnNth(args ...any) (any, error) {
 		return nil, errors.New("nth() second argument must be an integer")
 	}
 
-	pairs := strings.Split(str, ",")
-	if index < 0 || index >= len(pairs) {
-		return nil, nil
+	entries, err := tokenize(str)
+	if err != nil {
+		return nil, fmt.Errorf("nth() %w", err)
 	}
 
-	k, v := kv(pairs[index])
-	if v == "" {
-		return k, nil
+	if index < 0 || index >= len(entries) {
+		return nil, nil
 	}
 
-	return v, nil
+	return entries[index].content(), nil
 }
 
 func fnHas(args ...any) (any, error) {
@@ -178,6 +231,10 @@ func fnHas(args ...any) (any, error) {
 		return nil, errors.New("has() requires exactly 2 arguments")
 	}
 
+	if args[0] == nil {
+		return false, nil
+	}
+
 	str, ok := args[0].(string)
 	if !ok {
 		return nil, errors.New("has() first argument must be a string")
@@ -188,9 +245,13 @@ func fnHas(args ...any) (any, error) {
 		return nil, errors.New("has() second argument must be a string")
 	}
 
-	for pair := range strings.SplitSeq(str, ",") {
-		k, _ := kv(pair)
-		if k == key {
+	entries, err := tokenize(str)
+	if err != nil {
+		return nil, fmt.Errorf("has() %w", err)
+	}
+
+	for _, e := range entries {
+		if e.key == key {
 			return true, nil
 		}
 	}
@@ -203,6 +264,10 @@ func fnSplit(args ...any) (any, error) {
 		return nil, errors.New("split() requires at least 2 arguments")
 	}
 
+	if args[0] == nil {
+		return nil, nil
+	}
+
 	str, ok := args[0].(string)
 	if !ok {
 		return nil, errors.New("split() first argument must be a string")
@@ -3,35 +3,10 @@ package tiq
 import (
 	"testing"
 
+	"github.com/expr-lang/expr"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestKv(t *testing.T) {
-	t.Run("parses key=value pair", func(t *testing.T) {
-		k, v := kv("key=value")
-		assert.Equal(t, "key", k)
-		assert.Equal(t, "value", v)
-	})
-
-	t.Run("parses key without value", func(t *testing.T) {
-		k, v := kv("key")
-		assert.Equal(t, "key", k)
-		assert.Empty(t, v)
-	})
-
-	t.Run("trims whitespace", func(t *testing.T) {
-		k, v := kv("  key  =  value  ")
-		assert.Equal(t, "key", k)
-		assert.Equal(t, "value", v)
-	})
-
-	t.Run("handles value with equals sign", func(t *testing.T) {
-		k, v := kv("key=value=extra")
-		assert.Equal(t, "key", k)
-		assert.Equal(t, "value=extra", v)
-	})
-}
-
 func TestFnGet(t *testing.T) {
 	t.Run("returns value for existing key", func(t *testing.T) {
 		result, err := fnGet("key1=value1,key2=value2", "key1")
@@ -45,6 +20,12 @@ func TestFnGet(t *testing.T) {
 		assert.Nil(t, result)
 	})
 
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		result, err := fnGet(nil, "key1")
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
 	t.Run("returns error with wrong number of arguments", func(t *testing.T) {
 		_, err := fnGet("key1=value1")
 		assert.Error(t, err)
@@ -137,6 +118,12 @@ func TestFnHas(t *testing.T) {
 		assert.False(t, result.(bool))
 	})
 
+	t.Run("returns false for missing tag", func(t *testing.T) {
+		result, err := fnHas(nil, "key1")
+		assert.NoError(t, err)
+		assert.False(t, result.(bool))
+	})
+
 	t.Run("returns error when second argument is not string", func(t *testing.T) {
 		_, err := fnHas("key=value", 123)
 		assert.Error(t, err)
@@ -180,25 +167,49 @@ func TestFnDefault(t *testing.T) {
 
 func TestCompile(t *testing.T) {
 	t.Run("compiles simple variable reference", func(t *testing.T) {
-		program, err := compile("json")
+		program, err := compile("json", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, program)
 	})
 
 	t.Run("compiles function call", func(t *testing.T) {
-		program, err := compile("get(db, 'table')")
+		program, err := compile("get(db, 'table')", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, program)
 	})
 
 	t.Run("compiles has() function", func(t *testing.T) {
-		program, err := compile("has(validate, 'required')")
+		program, err := compile("has(validate, 'required')", nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, program)
 	})
 
+	t.Run("resolves tags named like a function when used as values", func(t *testing.T) {
+		fns := NewFunctions()
+		assert.NoError(t, fns.Register("custom", fnUpper, new(func(string) (string, error))))
+
+		tests := map[string]any{
+			"get(name, 'x')":            "1",
+			"name | get('x')":           "1",
+			"upper(option)":             "A=B",
+			"len(keys(time)) > 1":       true,
+			"custom(custom)":            "C",
+			"name | name()":             "x=1",
+			"let n = name; get(n, 'x')": "1",
+		}
+
+		for expression, expected := range tests {
+			program, err := compile(expression, nil, fns)
+			assert.NoError(t, err, expression)
+
+			output, err := expr.Run(program, map[string]any{"name": "x=1", "option": "a=b", "time": "a, b", "custom": "c"})
+			assert.NoError(t, err, expression)
+			assert.Equal(t, expected, output, expression)
+		}
+	})
+
 	t.Run("returns error for invalid expression", func(t *testing.T) {
-		_, err := compile("invalid(((")
+		_, err := compile("invalid(((", nil)
 		assert.ErrorIs(t, err, ErrCompileTag)
 		assert.Contains(t, err.Error(), "failed to compile")
 	})
@@ -244,6 +255,27 @@ func TestParseTags(t *testing.T) {
 		assert.True(t, schema.Required)
 	})
 
+	t.Run("parses tags named like a function", func(t *testing.T) {
+		type NamedSchema struct {
+			Column string `tag:"name | get('column')"`
+			Upper  string `tag:"upper(get(name, 'table'))"`
+		}
+
+		tags := map[string]string{"name": "table=users,column=id"}
+
+		schema, err := NewParser[NamedSchema](WithStrict()).ParseTags(tags)
+		assert.NoError(t, err)
+		assert.Equal(t, &NamedSchema{Column: "id", Upper: "USERS"}, schema)
+
+		schema, err = NewParser[NamedSchema](WithStrict(), WithTagKeys("name")).ParseTags(tags)
+		assert.NoError(t, err)
+		assert.Equal(t, &NamedSchema{Column: "id", Upper: "USERS"}, schema)
+
+		schema, err = NewParser[NamedSchema](WithStrict()).ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, &NamedSchema{}, schema)
+	})
+
 	t.Run("parses multiple schema fields", func(t *testing.T) {
 		tags := map[string]string{
 			"json":     "name",
@@ -1,6 +1,13 @@
 package tiq
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/expr-lang/expr/file"
+)
 
 var (
 	ErrNilValue      = errors.New("nil value provided")
@@ -9,6 +16,111 @@ var (
 
 	ErrFieldNotFound    = errors.New("field not found")
 	ErrFieldNotSettable = errors.New("field is not settable")
+	ErrInvalidPath      = errors.New("invalid field path")
 
 	ErrCompileTag = errors.New("cannot compile tag")
+	ErrEvalTag    = errors.New("cannot evaluate tag")
+	ErrRequired   = errors.New("required value is missing")
+	ErrTagType    = errors.New("mismatched tag type")
+
+	ErrUndeclaredTags  = errors.New("schema does not declare its tags")
+	ErrUnknownOption   = errors.New("unknown tag option")
+	ErrDuplicateOption = errors.New("duplicated tag option")
+	ErrMalformedTag    = errors.New("malformed tag")
+
+	ErrFunctionConflict = errors.New("function name conflict")
+	ErrInvalidFunction  = errors.New("invalid function")
 )
+
+// FieldError describes an error tied to a specific struct field.
+// It wraps the underlying error, so errors.Is can still be used to match the
+// sentinel errors above.
+//
+// For errors coming from a schema expression, StructType and FieldPath locate
+// the schema field holding the expression, and Source is the path of the field
+// whose tags were being parsed, if any. FieldPath is empty when the error is
+// only tied to the parsed field, like a malformed struct tag.
+type FieldError struct {
+	StructType reflect.Type
+	FieldPath  string
+	Source     string
+	TagKey     string
+	Expression string
+
+	// Line and Column locate the error in Expression, if known.
+	Line   int
+	Column int
+
+	Err error
+}
+
+func newFieldError(structType reflect.Type, fieldPath string, err error) *FieldError {
+	e := &FieldError{
+		StructType: structType,
+		FieldPath:  fieldPath,
+		Err:        err,
+	}
+
+	var fileErr *file.Error
+	if errors.As(err, &fileErr) {
+		e.Line = fileErr.Line
+		e.Column = fileErr.Column
+	}
+
+	return e
+}
+
+func (e *FieldError) Error() string {
+	var b strings.Builder
+
+	if e.StructType != nil {
+		b.WriteString(e.StructType.String())
+		if e.FieldPath != "" {
+			b.WriteByte('.')
+		}
+	}
+	b.WriteString(e.FieldPath)
+
+	if e.Source != "" {
+		fmt.Fprintf(&b, " (parsing field %s)", e.Source)
+	}
+
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is a list of errors, returned when a Parser aggregates every error it
+// finds instead of stopping at the first one (see WithAggregateErrors).
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// flatten merges nested Errors into a single list.
+func (e Errors) flatten() Errors {
+	flat := Errors{}
+	for _, err := range e {
+		if nested, ok := err.(Errors); ok {
+			flat = append(flat, nested.flatten()...)
+			continue
+		}
+
+		flat = append(flat, err)
+	}
+
+	return flat
+}
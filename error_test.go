@@ -0,0 +1,114 @@
+package tiq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/expr-lang/expr/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldError(t *testing.T) {
+	t.Run("formats struct type, path and source", func(t *testing.T) {
+		type Schema struct{}
+
+		err := &FieldError{
+			StructType: reflect.TypeFor[Schema](),
+			FieldPath:  "Name",
+			Source:     "Url",
+			Err:        ErrEvalTag,
+		}
+
+		assert.Equal(t, "tiq.Schema.Name (parsing field Url): cannot evaluate tag", err.Error())
+	})
+
+	t.Run("formats without struct type", func(t *testing.T) {
+		err := &FieldError{FieldPath: "Name", Err: ErrCannotConvert}
+		assert.Equal(t, "Name: cannot convert value", err.Error())
+	})
+
+	t.Run("matches wrapped sentinels", func(t *testing.T) {
+		var err error = &FieldError{FieldPath: "Name", Err: errors.Join(ErrCompileTag, errors.New("boom"))}
+		assert.ErrorIs(t, err, ErrCompileTag)
+	})
+
+	t.Run("is returned for compile errors with position", func(t *testing.T) {
+		type BadSchema struct {
+			Valid string `tag:"json"`
+			Field string `tag:"json | get('a'"`
+		}
+
+		_, err := parseTags[BadSchema](map[string]string{})
+		assert.ErrorIs(t, err, ErrCompileTag)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, reflect.TypeFor[BadSchema](), fieldErr.StructType)
+		assert.Equal(t, "Field", fieldErr.FieldPath)
+		assert.Equal(t, "tag", fieldErr.TagKey)
+		assert.Equal(t, "json | get('a'", fieldErr.Expression)
+		assert.Equal(t, 1, fieldErr.Line)
+		assert.Equal(t, 13, fieldErr.Column)
+
+		var exprErr *file.Error
+		assert.ErrorAs(t, err, &exprErr)
+	})
+
+	t.Run("is returned for conversion errors", func(t *testing.T) {
+		type Schema struct {
+			Port int `tag:"env | get('port')"`
+		}
+		type Config struct {
+			Port int `env:"port=http"`
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		_, err = Parse[Schema](inspector.Fields()[0])
+		assert.ErrorIs(t, err, ErrCannotConvert)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Port", fieldErr.FieldPath)
+		assert.Equal(t, "Port", fieldErr.Source)
+		assert.Equal(t, "env | get('port')", fieldErr.Expression)
+	})
+
+	t.Run("is returned by Set", func(t *testing.T) {
+		type Config struct {
+			Port int
+		}
+
+		err := Set(&Config{}, "Port", "http")
+		assert.ErrorIs(t, err, ErrCannotConvert)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, reflect.TypeFor[Config](), fieldErr.StructType)
+		assert.Equal(t, "Port", fieldErr.FieldPath)
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("joins messages", func(t *testing.T) {
+		err := Errors{errors.New("first"), errors.New("second")}
+		assert.Equal(t, "first\nsecond", err.Error())
+	})
+
+	t.Run("matches every wrapped error", func(t *testing.T) {
+		var err error = Errors{ErrCompileTag, &FieldError{FieldPath: "Port", Err: ErrCannotConvert}}
+		assert.ErrorIs(t, err, ErrCompileTag)
+		assert.ErrorIs(t, err, ErrCannotConvert)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Port", fieldErr.FieldPath)
+	})
+
+	t.Run("flattens nested errors", func(t *testing.T) {
+		err := Errors{ErrCompileTag, Errors{ErrEvalTag, Errors{ErrCannotConvert}}}
+		assert.Equal(t, Errors{ErrCompileTag, ErrEvalTag, ErrCannotConvert}, err.flatten())
+	})
+}
@@ -2,43 +2,57 @@ package tiq
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
-	"strings"
-
-	"github.com/AnatoleLucet/as"
+	"slices"
 )
 
 type Field struct {
 	reflect.Value
 	reflect.StructField
+
+	path string
+
+	// mapValue and mapKey are set when the field points to a map entry,
+	// since map entries can't be set through reflect.Value.Set.
+	mapValue reflect.Value
+	mapKey   reflect.Value
+
+	// info holds the cached metadata of the field, if known.
+	info *fieldInfo
 }
 
-// Tags parses and returns every tag of the field as a map.
-func (f *Field) Tags() (map[string]string, error) {
-	var tags = make(map[string]string)
+// Path returns the dot separated path of the field from the inspected struct
+// (e.g. "Database.Host"). For top-level fields, this is the field's name.
+func (f *Field) Path() string {
+	return f.path
+}
 
-	structTag := string(f.StructField.Tag)
-	if structTag == "" {
-		return tags, nil
+// Tags parses and returns every tag of the field as a map. If a key is
+// repeated, its first value is returned (like reflect.StructTag.Lookup).
+//
+// Malformed struct tags return an error wrapping ErrMalformedTag, along with
+// the tags preceding the malformed part.
+func (f *Field) Tags() (map[string]string, error) {
+	if f.cached() {
+		return maps.Clone(f.info.tags), f.info.tagsErr
 	}
 
-	for segment := range strings.FieldsSeq(structTag) {
-		colonIdx := strings.Index(segment, ":")
-		if colonIdx == -1 {
-			continue
-		}
-
-		key := segment[:colonIdx]
-		value, ok := f.StructField.Tag.Lookup(key)
-		if !ok {
-			continue
-		}
+	return structTags(f.StructField.Tag)
+}
 
-		tags[key] = value
+// OrderedTags is like Tags, but returns every tag in declared order.
+func (f *Field) OrderedTags() ([]Tag, error) {
+	if f.cached() {
+		return slices.Clone(f.info.ordered), f.info.tagsErr
 	}
 
-	return tags, nil
+	return lexStructTag(f.StructField.Tag)
+}
 
+// cached reports whether the field's tags can be read from its cached metadata.
+func (f *Field) cached() bool {
+	return f.info != nil && f.info.field.Tag == f.StructField.Tag
 }
 
 // Tag returns the tag value of the given name and whether it was found or not.
@@ -48,6 +62,10 @@ func (f *Field) Tag(name string) (string, bool) {
 
 // Set updates the field's value to the provided value.
 func (f *Field) Set(value any) error {
+	if f.mapValue.IsValid() {
+		return f.setMapIndex(value)
+	}
+
 	if !f.Value.CanSet() {
 		return ErrFieldNotSettable
 	}
@@ -62,25 +80,34 @@ func (f *Field) Set(value any) error {
 }
 
 // SetFrom updates the field's value to the provided value after converting it to the appropriate type.
-// See as.Type for supported conversions.
+// See as.Type for supported conversions. Additionally, maps are decoded into
+// structs (matching keys to field names case-insensitively), and slices of
+// maps into slices of structs.
 func (f *Field) SetFrom(value any) error {
-	typ := f.Value.Type()
-	isPtr := typ.Kind() == reflect.Pointer
+	v, err := convert(f.Value.Type(), value)
+	if err != nil {
+		return fmt.Errorf("%w: cannot convert %T to %s: %v", ErrCannotConvert, value, f.Value.Type(), err)
+	}
+
+	return f.Set(v.Interface())
+}
 
-	if isPtr {
-		typ = typ.Elem()
+func (f *Field) setMapIndex(value any) error {
+	if !f.mapValue.CanSet() {
+		return ErrFieldNotSettable
 	}
 
-	v, err := as.Type(typ, value)
-	if err != nil {
-		return fmt.Errorf("%w: cannot convert %T to %s: %v", ErrCannotConvert, value, f.Value.Type(), err)
+	typ := f.mapValue.Type().Elem()
+	v := reflect.ValueOf(value)
+	if !v.CanConvert(typ) {
+		return fmt.Errorf("%w: cannot convert %s to %s", ErrCannotConvert, v.Type(), typ)
 	}
 
-	if isPtr {
-		ptr := reflect.New(typ)
-		ptr.Elem().Set(reflect.ValueOf(v).Convert(typ))
-		v = ptr.Interface()
+	if f.mapValue.IsNil() {
+		f.mapValue.Set(reflect.MakeMap(f.mapValue.Type()))
 	}
 
-	return f.Set(v)
+	f.Value = v.Convert(typ)
+	f.mapValue.SetMapIndex(f.mapKey, f.Value)
+	return nil
 }
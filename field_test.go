@@ -1,6 +1,7 @@
 package tiq
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -75,6 +76,61 @@ func TestField_Tags(t *testing.T) {
 		assert.Equal(t, "field1,omitempty", tags["json"])
 		assert.Equal(t, "min=5,max=10", tags["validate"])
 	})
+
+	t.Run("handles values containing spaces and key-like text", func(t *testing.T) {
+		type TestStruct struct {
+			Field1 string `doc:"the user name, see key:\"x\"" env:"name=X"`
+		}
+
+		inspector, err := Inspect(TestStruct{})
+		assert.NoError(t, err)
+
+		tags, err := inspector.Fields()[0].Tags()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"doc": `the user name, see key:"x"`, "env": "name=X"}, tags)
+	})
+
+	t.Run("returns error for malformed tags", func(t *testing.T) {
+		// built at runtime, since go vet rejects malformed tags
+		typ := reflect.StructOf([]reflect.StructField{
+			{Name: "Field1", Type: reflect.TypeFor[string](), Tag: `json:"field1" env:name`},
+		})
+
+		inspector, err := Inspect(reflect.New(typ).Interface())
+		assert.NoError(t, err)
+
+		tags, err := inspector.Fields()[0].Tags()
+		assert.ErrorIs(t, err, ErrMalformedTag)
+		assert.EqualError(t, err, `malformed tag: key "env" is not followed by a quoted value at offset 14`)
+		assert.Equal(t, map[string]string{"json": "field1"}, tags)
+	})
+}
+
+func TestField_OrderedTags(t *testing.T) {
+	typ := reflect.StructOf([]reflect.StructField{
+		{Name: "Field1", Type: reflect.TypeFor[string](), Tag: `yaml:"b" json:"a" env:"c"`},
+		{Name: "Field2", Type: reflect.TypeFor[string](), Tag: `json:"a" env:name`},
+	})
+
+	inspector, err := Inspect(reflect.New(typ).Interface())
+	assert.NoError(t, err)
+
+	t.Run("returns tags in declared order", func(t *testing.T) {
+		tags, err := inspector.Fields()[0].OrderedTags()
+		assert.NoError(t, err)
+		assert.Equal(t, []Tag{{Key: "yaml", Value: "b"}, {Key: "json", Value: "a"}, {Key: "env", Value: "c"}}, tags)
+
+		tags[0].Key = "changed"
+		tags, err = inspector.Fields()[0].OrderedTags()
+		assert.NoError(t, err)
+		assert.Equal(t, "yaml", tags[0].Key)
+	})
+
+	t.Run("returns error for malformed tags", func(t *testing.T) {
+		tags, err := inspector.Fields()[1].OrderedTags()
+		assert.ErrorIs(t, err, ErrMalformedTag)
+		assert.Equal(t, []Tag{{Key: "json", Value: "a"}}, tags)
+	})
 }
 
 func TestField_Tag(t *testing.T) {
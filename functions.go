@@ -0,0 +1,124 @@
+package tiq
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/expr-lang/expr"
+)
+
+// Functions is a registry of custom DSL functions that can be used in schema
+// expressions alongside tiq's built-in functions.
+type Functions struct {
+	mu  sync.RWMutex
+	fns map[string]function
+
+	plans sync.Map // map[planKey]*plan, see planFor
+}
+
+type function struct {
+	fn    func(args ...any) (any, error)
+	types []any
+}
+
+// globalFunctions holds the functions registered with RegisterFunction.
+var globalFunctions = NewFunctions()
+
+// NewFunctions returns an empty function registry.
+func NewFunctions() *Functions {
+	return &Functions{fns: map[string]function{}}
+}
+
+// RegisterFunction registers a function available in every schema expression.
+// See Functions.Register.
+func RegisterFunction(name string, fn func(args ...any) (any, error), types ...any) error {
+	return globalFunctions.Register(name, fn, types...)
+}
+
+// Register adds a named function to the registry.
+// Types optionally declare the function's signatures so expressions can be
+// type checked at compile time, e.g. new(func(string) (time.Duration, error)).
+// It returns ErrFunctionConflict if the name is already used by a built-in
+// or a previously registered function.
+func (f *Functions) Register(name string, fn func(args ...any) (any, error), types ...any) error {
+	if !isIdentifier(name) {
+		return fmt.Errorf("%w: %q is not a valid function name", ErrInvalidFunction, name)
+	}
+	if fn == nil {
+		return fmt.Errorf("%w: %s() has no implementation", ErrInvalidFunction, name)
+	}
+
+	for _, typ := range types {
+		t := reflect.TypeOf(typ)
+		if t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Func {
+			return fmt.Errorf("%w: %s() type %T is not a function", ErrInvalidFunction, name, typ)
+		}
+	}
+
+	if _, ok := builtins[name]; ok {
+		return fmt.Errorf("%w: %s() is a built-in function", ErrFunctionConflict, name)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.fns[name]; ok {
+		return fmt.Errorf("%w: %s() is already registered", ErrFunctionConflict, name)
+	}
+
+	f.fns[name] = function{fn, types}
+	return nil
+}
+
+// Names returns the name of every function in the registry.
+func (f *Functions) Names() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	names := make([]string, 0, len(f.fns))
+	for name := range f.fns {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// options returns the expr options declaring the registry's functions.
+// Functions already declared in seen are reported as conflicts.
+func (f *Functions) options(seen map[string]bool) ([]expr.Option, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	opts := make([]expr.Option, 0, len(f.fns))
+	for name, fn := range f.fns {
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %s() is registered more than once", ErrFunctionConflict, name)
+		}
+		seen[name] = true
+
+		opts = append(opts, expr.Function(name, fn.fn, fn.types...))
+	}
+
+	return opts, nil
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,119 @@
+package tiq
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fnShout(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("shout() requires exactly 1 argument")
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("shout() argument must be a string")
+	}
+
+	return strings.ToUpper(str) + "!", nil
+}
+
+func TestFunctions_Register(t *testing.T) {
+	t.Run("registers a function", func(t *testing.T) {
+		fns := NewFunctions()
+
+		err := fns.Register("shout", fnShout, new(func(string) (string, error)))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"shout"}, fns.Names())
+	})
+
+	t.Run("returns error when name conflicts with a built-in", func(t *testing.T) {
+		fns := NewFunctions()
+
+		err := fns.Register("get", fnShout)
+		assert.ErrorIs(t, err, ErrFunctionConflict)
+		assert.Contains(t, err.Error(), "get() is a built-in function")
+	})
+
+	t.Run("returns error when name is already registered", func(t *testing.T) {
+		fns := NewFunctions()
+
+		assert.NoError(t, fns.Register("shout", fnShout))
+
+		err := fns.Register("shout", fnShout)
+		assert.ErrorIs(t, err, ErrFunctionConflict)
+		assert.Contains(t, err.Error(), "already registered")
+	})
+
+	t.Run("returns error for invalid names", func(t *testing.T) {
+		fns := NewFunctions()
+
+		for _, name := range []string{"", "1abc", "foo-bar", "foo bar"} {
+			err := fns.Register(name, fnShout)
+			assert.ErrorIs(t, err, ErrInvalidFunction, name)
+		}
+	})
+
+	t.Run("returns error for invalid types", func(t *testing.T) {
+		fns := NewFunctions()
+
+		err := fns.Register("shout", fnShout, "not a function")
+		assert.ErrorIs(t, err, ErrInvalidFunction)
+
+		err = fns.Register("shout", nil)
+		assert.ErrorIs(t, err, ErrInvalidFunction)
+	})
+}
+
+func TestRegisterFunction(t *testing.T) {
+	t.Run("makes the function available in schema expressions", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"env | get('name') | globalShout()"`
+		}
+
+		err := RegisterFunction("globalShout", fnShout, new(func(string) (string, error)))
+		assert.NoError(t, err)
+
+		schema, err := parseTags[Schema](map[string]string{"env": "name=port"})
+		assert.NoError(t, err)
+		assert.Equal(t, "PORT!", schema.Name)
+	})
+
+	t.Run("returns error when name conflicts with a built-in", func(t *testing.T) {
+		err := RegisterFunction("split", fnShout)
+		assert.ErrorIs(t, err, ErrFunctionConflict)
+	})
+}
+
+func TestCompileWithFunctions(t *testing.T) {
+	t.Run("compiles with custom functions", func(t *testing.T) {
+		fns := NewFunctions()
+		assert.NoError(t, fns.Register("shout", fnShout, new(func(string) (string, error))))
+
+		program, err := compile("shout(json)", nil, fns)
+		assert.NoError(t, err)
+		assert.NotNil(t, program)
+	})
+
+	t.Run("type checks arguments against declared signatures", func(t *testing.T) {
+		fns := NewFunctions()
+		assert.NoError(t, fns.Register("shout", fnShout, new(func(string) (string, error))))
+
+		_, err := compile("shout(1)", nil, fns)
+		assert.ErrorIs(t, err, ErrCompileTag)
+	})
+
+	t.Run("returns error when a function is registered in several registries", func(t *testing.T) {
+		a := NewFunctions()
+		assert.NoError(t, a.Register("shout", fnShout))
+		b := NewFunctions()
+		assert.NoError(t, b.Register("shout", fnShout))
+
+		_, err := compile("shout(json)", nil, a, b)
+		assert.ErrorIs(t, err, ErrCompileTag)
+		assert.ErrorIs(t, err, ErrFunctionConflict)
+	})
+}
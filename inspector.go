@@ -1,6 +1,7 @@
 package tiq
 
 import (
+	"iter"
 	"reflect"
 )
 
@@ -30,35 +31,49 @@ func Inspect(value any) (*Inspector, error) {
 // Fields returns every fields of the struct.
 func (i *Inspector) Fields() []*Field {
 	fields := []*Field{}
-
-	v := i.value
-	t := i.value.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		fields = append(fields, &Field{
-			v.Field(i),
-			t.Field(i),
-		})
+	for field := range i.All() {
+		fields = append(fields, field)
 	}
 
 	return fields
 }
 
-// Field returns the field with the given name, or nil if it doesn't exist.
-func (i *Inspector) Field(name string) (*Field, bool) {
-	v := i.value
-	t := i.value.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		if t.Field(i).Name == name {
-			return &Field{
-				v.Field(i),
-				t.Field(i),
-			}, true
+// All returns an iterator over every field of the struct.
+func (i *Inspector) All() iter.Seq[*Field] {
+	return func(yield func(*Field) bool) {
+		for _, field := range i.Named() {
+			if !yield(field) {
+				return
+			}
 		}
 	}
+}
+
+// Named returns an iterator over every field of the struct along with its name.
+func (i *Inspector) Named() iter.Seq2[string, *Field] {
+	return func(yield func(string, *Field) bool) {
+		v := i.value
+		info := typeInfoOf(v.Type())
+
+		for i := range info.fields {
+			field := info.field(v, i, "")
+
+			if !yield(field.Name, field) {
+				return
+			}
+		}
+	}
+}
+
+// Field returns the field at the given path, or nil if it doesn't exist.
+// See Inspector.Lookup for the path syntax.
+func (i *Inspector) Field(path string) (*Field, bool) {
+	field, err := i.Lookup(path)
+	if err != nil {
+		return nil, false
+	}
 
-	return nil, false
+	return field, true
 }
 
 func isStruct(v any) bool {
@@ -211,3 +211,96 @@ func TestInspector_FieldOperations(t *testing.T) {
 		assert.Equal(t, "field_2", tags["db"])
 	})
 }
+
+func TestInspector_All(t *testing.T) {
+	type TestStruct struct {
+		Field1 string `json:"field1"`
+		Field2 int    `json:"field2"`
+		Field3 bool   `json:"field3"`
+	}
+
+	t.Run("yields every field in order", func(t *testing.T) {
+		inspector, err := Inspect(TestStruct{})
+		assert.NoError(t, err)
+
+		names := []string{}
+		for field := range inspector.All() {
+			names = append(names, field.Name)
+		}
+		assert.Equal(t, []string{"Field1", "Field2", "Field3"}, names)
+	})
+
+	t.Run("stops when the loop breaks", func(t *testing.T) {
+		inspector, err := Inspect(TestStruct{})
+		assert.NoError(t, err)
+
+		names := []string{}
+		for field := range inspector.All() {
+			names = append(names, field.Name)
+			if field.Name == "Field2" {
+				break
+			}
+		}
+		assert.Equal(t, []string{"Field1", "Field2"}, names)
+	})
+
+	t.Run("yields settable fields", func(t *testing.T) {
+		testStruct := TestStruct{}
+		inspector, err := Inspect(&testStruct)
+		assert.NoError(t, err)
+
+		for field := range inspector.All() {
+			if field.Name == "Field2" {
+				assert.NoError(t, field.Set(42))
+			}
+		}
+		assert.Equal(t, 42, testStruct.Field2)
+	})
+}
+
+func TestInspector_Named(t *testing.T) {
+	type TestStruct struct {
+		Field1 string `json:"field1"`
+		Field2 int    `json:"field2"`
+	}
+
+	t.Run("yields every field with its name", func(t *testing.T) {
+		inspector, err := Inspect(TestStruct{})
+		assert.NoError(t, err)
+
+		tags := map[string]string{}
+		for name, field := range inspector.Named() {
+			tags[name], _ = field.Tag("json")
+		}
+		assert.Equal(t, map[string]string{"Field1": "field1", "Field2": "field2"}, tags)
+	})
+
+	t.Run("stops when the loop breaks", func(t *testing.T) {
+		inspector, err := Inspect(TestStruct{})
+		assert.NoError(t, err)
+
+		count := 0
+		for range inspector.Named() {
+			count++
+			break
+		}
+		assert.Equal(t, 1, count)
+	})
+}
+
+func BenchmarkInspector_Field(b *testing.B) {
+	type LargeStruct struct {
+		F00, F01, F02, F03, F04, F05, F06, F07, F08, F09 string
+		F10, F11, F12, F13, F14, F15, F16, F17, F18, F19 string
+		F20, F21, F22, F23, F24, F25, F26, F27, F28, F29 string
+	}
+
+	inspector, err := Inspect(&LargeStruct{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		_, _ = inspector.Field("F29")
+	}
+}
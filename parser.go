@@ -0,0 +1,407 @@
+package tiq
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/expr-lang/expr"
+)
+
+// Parser parses fields' tags into a Schema, according to its options.
+// A Parser is safe for concurrent use.
+type Parser[Schema any] struct {
+	config *config
+}
+
+// Option configures a Parser.
+type Option func(*config)
+
+type config struct {
+	tagName   string
+	strict    bool
+	functions *Functions
+	variables map[string]any
+	cache     bool
+	aggregate bool
+
+	skipUntagged bool
+	tagKeys      []string
+
+	// typeCheck makes plans statically check expression output types, see ValidateSchema.
+	typeCheck bool
+}
+
+// WithTagName sets the name of the schema tag holding expressions. Defaults to "tag".
+// Related schema tags are named after it (e.g. "tagdefault" becomes "<name>default").
+func WithTagName(name string) Option {
+	return func(c *config) {
+		c.tagName = name
+	}
+}
+
+// WithStrict makes the parser return errors happening while evaluating
+// expressions, instead of leaving the schema field to its zero value.
+func WithStrict() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// WithFunctions makes the functions of the given registry available to the
+// parser's expressions, in addition to the globally registered ones.
+// Compiled expressions are cached on the registry, so parsers sharing a
+// long-lived registry only compile a schema once.
+func WithFunctions(functions *Functions) Option {
+	return func(c *config) {
+		c.functions = functions
+	}
+}
+
+// WithVariables exposes extra variables to the parser's expressions.
+// Tags take precedence over variables of the same name.
+func WithVariables(variables map[string]any) Option {
+	return func(c *config) {
+		c.variables = variables
+	}
+}
+
+// WithCache enables or disables caching compiled expressions across calls. Enabled by default.
+func WithCache(enabled bool) Option {
+	return func(c *config) {
+		c.cache = enabled
+	}
+}
+
+// WithAggregateErrors makes the parser keep going after an error, and return
+// every error found as an Errors, instead of stopping at the first one.
+func WithAggregateErrors() Option {
+	return func(c *config) {
+		c.aggregate = true
+	}
+}
+
+// WithTagKeys declares the tags expressions can read, which makes them type
+// checked when compiled: unknown variables (e.g. a typo like `evn`), mismatched
+// function arguments and outputs not matching their schema field are reported
+// as compile errors. Schemas can also declare their tags by implementing TagKeyer.
+func WithTagKeys(keys ...string) Option {
+	return func(c *config) {
+		c.tagKeys = keys
+	}
+}
+
+// TagKeyer is implemented by schemas declaring the tags their expressions can
+// read. See WithTagKeys.
+type TagKeyer interface {
+	TagKeys() []string
+}
+
+// WithSkipUntagged makes ParseAll and ParseStruct skip fields that have none
+// of the tags referenced by the schema's expressions.
+func WithSkipUntagged() Option {
+	return func(c *config) {
+		c.skipUntagged = true
+	}
+}
+
+// NewParser returns a Parser for the given Schema.
+func NewParser[Schema any](opts ...Option) *Parser[Schema] {
+	return &Parser[Schema]{newConfig(opts...)}
+}
+
+func newConfig(opts ...Option) *config {
+	c := &config{
+		tagName: "tag",
+		cache:   true,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Parse evaluates the schema against the tags of the given field.
+func (p *Parser[Schema]) Parse(field *Field) (*Schema, error) {
+	pl, err := planFor(reflect.TypeFor[Schema](), p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parseField(pl, field)
+}
+
+// ParseTags evaluates the schema against the given tags, where keys are tag
+// names and values are their content (e.g. {"json": "name,omitempty"}).
+func (p *Parser[Schema]) ParseTags(tags map[string]string) (*Schema, error) {
+	pl, err := planFor(reflect.TypeFor[Schema](), p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parse(pl, tags, nil)
+}
+
+// Result pairs a struct field with the schema parsed from its tags.
+type Result[Schema any] struct {
+	Field  *Field
+	Schema *Schema
+}
+
+// ParseAll parses every field of the given struct, and returns them along
+// with their schemas in the same order as Inspector.Fields.
+func (p *Parser[Schema]) ParseAll(value any) ([]Result[Schema], error) {
+	inspector, err := Inspect(value)
+	if err != nil {
+		return nil, err
+	}
+
+	pl, err := planFor(reflect.TypeFor[Schema](), p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := inspector.Fields()
+	results := make([]Result[Schema], 0, len(fields))
+	errs := Errors{}
+
+	for _, field := range fields {
+		if p.config.skipUntagged && !p.references(pl, field) {
+			continue
+		}
+
+		schema, err := p.parseField(pl, field)
+		if err != nil {
+			if !p.config.aggregate {
+				return nil, err
+			}
+
+			errs = append(errs, err)
+			continue
+		}
+
+		results = append(results, Result[Schema]{field, schema})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs.flatten()
+	}
+
+	return results, nil
+}
+
+// ParseStruct is like ParseAll, but only returns the parsed schemas.
+func (p *Parser[Schema]) ParseStruct(value any) ([]*Schema, error) {
+	results, err := p.ParseAll(value)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make([]*Schema, len(results))
+	for i, result := range results {
+		schemas[i] = result.Schema
+	}
+
+	return schemas, nil
+}
+
+// references reports whether the field has any of the tags referenced by the
+// schema's expressions.
+func (p *Parser[Schema]) references(pl *plan, field *Field) bool {
+	for _, tag := range pl.tags {
+		if _, ok := p.config.variables[tag]; ok {
+			continue
+		}
+
+		if _, ok := field.Tag(tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Parser[Schema]) parseField(pl *plan, field *Field) (*Schema, error) {
+	tags, err := field.Tags()
+	if err != nil {
+		// not tied to any schema field, only to the parsed one
+		e := newFieldError(pl.schema, "", err)
+		e.Source = field.Path()
+		return nil, e
+	}
+
+	return p.parse(pl, tags, field)
+}
+
+// parse evaluates the plan against the given tags. The source field is only
+// used to give context to errors, and may be nil.
+func (p *Parser[Schema]) parse(pl *plan, tags map[string]string, source *Field) (*Schema, error) {
+	env := make(map[string]any, len(p.config.variables)+len(tags)+1)
+	maps.Copy(env, p.config.variables)
+	for k, v := range tags {
+		env[k] = v
+	}
+	if source != nil {
+		env[fieldVariable] = fieldMetadata(source)
+	}
+
+	schema := new(Schema)
+	value := reflect.ValueOf(schema).Elem()
+	errs := Errors{}
+
+	for _, pf := range pl.fields {
+		output, err := p.eval(pf, env)
+		if err != nil {
+			errs = append(errs, p.fieldError(pl, pf, source, err))
+			if !p.config.aggregate {
+				break
+			}
+			continue
+		}
+		if output == nil {
+			continue
+		}
+
+		f := &Field{Value: fieldByIndex(value, pf.index), StructField: pf.field, path: pf.path}
+		err = f.SetFrom(output)
+		if err != nil {
+			errs = append(errs, p.fieldError(pl, pf, source, err))
+			if !p.config.aggregate {
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, p.config.err(errs)
+	}
+
+	return schema, nil
+}
+
+// eval runs the field's expression, and falls back to its default value when
+// the expression yields nothing. Required fields fail if they still have no value.
+func (p *Parser[Schema]) eval(pf planField, env map[string]any) (any, error) {
+	var output any
+
+	if pf.program != nil {
+		var err error
+		output, err = expr.Run(pf.program, env)
+		if err != nil {
+			if p.config.strict {
+				return nil, fmt.Errorf("%w: expression %q: %w", ErrEvalTag, pf.expression, err)
+			}
+			output = nil
+		}
+	}
+
+	if output == nil && pf.hasDefault {
+		output = pf.defaultValue
+	}
+
+	if pf.required && isEmpty(output) {
+		if len(pf.tags) == 0 {
+			return nil, fmt.Errorf("%w: no value", ErrRequired)
+		}
+		return nil, fmt.Errorf("%w: no value found in tags %s", ErrRequired, strings.Join(pf.tags, ", "))
+	}
+
+	return output, nil
+}
+
+// isEmpty reports whether an expression output is nil or has no content.
+func isEmpty(output any) bool {
+	if output == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(output)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// fieldVariable is the name of the variable exposing the parsed field's
+// metadata to expressions.
+const fieldVariable = "$field"
+
+// fieldMetadata returns the metadata of the field exposed to expressions
+// as $field.
+func fieldMetadata(field *Field) map[string]any {
+	index := 0
+	if n := len(field.StructField.Index); n > 0 {
+		index = field.StructField.Index[n-1]
+	}
+
+	return map[string]any{
+		"name":     field.StructField.Name,
+		"type":     field.StructField.Type.String(),
+		"kind":     field.StructField.Type.Kind().String(),
+		"index":    index,
+		"path":     field.Path(),
+		"exported": field.StructField.IsExported(),
+	}
+}
+
+// fieldError wraps an error happening while evaluating the given plan field.
+func (p *Parser[Schema]) fieldError(pl *plan, pf planField, source *Field, err error) *FieldError {
+	e := newFieldError(pl.schema, pf.path, err)
+	e.TagKey = p.config.tagName
+	e.Expression = pf.expression
+	if source != nil {
+		e.Source = source.Path()
+	}
+
+	return e
+}
+
+// err returns the given errors as a single error, according to the config's
+// aggregation mode.
+func (c *config) err(errs Errors) error {
+	if !c.aggregate {
+		return errs[0]
+	}
+
+	return errs
+}
+
+// env returns the type of every variable available to the schema's
+// expressions, or nil if its tags are not declared. See WithTagKeys.
+func (c *config) env(schema reflect.Type) map[string]any {
+	keys := c.tagKeys
+	if keys == nil {
+		if keyer, ok := reflect.New(schema).Interface().(TagKeyer); ok {
+			keys = keyer.TagKeys()
+		}
+	}
+	if keys == nil {
+		return nil
+	}
+
+	env := make(map[string]any, len(c.variables)+len(keys)+1)
+	maps.Copy(env, c.variables)
+	for _, key := range keys {
+		env[key] = ""
+	}
+	env[fieldVariable] = map[string]any{}
+
+	return env
+}
+
+// defaultTagName returns the name of the schema tag holding default values.
+func (c *config) defaultTagName() string {
+	return c.tagName + "default"
+}
+
+// requiredTagName returns the name of the schema tag marking required fields.
+func (c *config) requiredTagName() string {
+	return c.tagName + "required"
+}
@@ -0,0 +1,668 @@
+package tiq
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Parse(t *testing.T) {
+	type Schema struct {
+		Name     string `tag:"env | get('name')"`
+		Optional bool   `tag:"env | has('optional')"`
+	}
+
+	t.Run("parses field tags", func(t *testing.T) {
+		type Config struct {
+			Url string `env:"name=URL, optional"`
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		field, ok := inspector.Field("Url")
+		assert.True(t, ok)
+
+		schema, err := NewParser[Schema]().Parse(field)
+		assert.NoError(t, err)
+		assert.Equal(t, "URL", schema.Name)
+		assert.True(t, schema.Optional)
+	})
+
+	t.Run("returns runtime errors annotated with the source field in strict mode", func(t *testing.T) {
+		type StrictSchema struct {
+			Port string `tag:"env.port"`
+		}
+		type Config struct {
+			Port int `env:"name=PORT"`
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		_, err = NewParser[StrictSchema](WithStrict()).Parse(inspector.Fields()[0])
+		assert.ErrorIs(t, err, ErrEvalTag)
+		assert.Contains(t, err.Error(), "tiq.StrictSchema.Port (parsing field Port)")
+		assert.Contains(t, err.Error(), `expression "env.port"`)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Port", fieldErr.FieldPath)
+		assert.Equal(t, "Port", fieldErr.Source)
+	})
+
+	t.Run("returns error when the field's struct tag is malformed", func(t *testing.T) {
+		typ := reflect.StructOf([]reflect.StructField{
+			{Name: "Port", Type: reflect.TypeFor[int](), Tag: `env:"name=PORT`},
+		})
+
+		inspector, err := Inspect(reflect.New(typ).Interface())
+		assert.NoError(t, err)
+
+		_, err = NewParser[Schema]().Parse(inspector.Fields()[0])
+		assert.ErrorIs(t, err, ErrMalformedTag)
+		assert.EqualError(t, err, `tiq.Schema (parsing field Port): malformed tag: unterminated value of key "env" at offset 4`)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Empty(t, fieldErr.FieldPath)
+		assert.Equal(t, "Port", fieldErr.Source)
+	})
+
+	t.Run("is used by tiq.Parse", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"name=PORT"`
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		schema, err := Parse[Schema](inspector.Fields()[0])
+		assert.NoError(t, err)
+		assert.Equal(t, "PORT", schema.Name)
+		assert.False(t, schema.Optional)
+	})
+}
+
+func TestParser_ParseTags(t *testing.T) {
+	t.Run("uses custom tag name", func(t *testing.T) {
+		type Schema struct {
+			Name  string `expr:"env | get('name')"`
+			Other string `tag:"env"`
+		}
+
+		schema, err := NewParser[Schema](WithTagName("expr")).ParseTags(map[string]string{"env": "name=URL"})
+		assert.NoError(t, err)
+		assert.Equal(t, "URL", schema.Name)
+		assert.Empty(t, schema.Other)
+	})
+
+	t.Run("skips runtime errors by default", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"split(env, ',')[3]"`
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"env": "a,b"})
+		assert.NoError(t, err)
+		assert.Empty(t, schema.Name)
+	})
+
+	t.Run("returns runtime errors in strict mode", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"split(env, ',')[3]"`
+		}
+
+		_, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{"env": "a,b"})
+		assert.ErrorIs(t, err, ErrEvalTag)
+		assert.Contains(t, err.Error(), "tiq.Schema.Name: ")
+		assert.Contains(t, err.Error(), `expression "split(env, ',')[3]"`)
+		assert.Contains(t, err.Error(), "index out of range")
+	})
+
+	t.Run("does not fail on missing tags in strict mode", func(t *testing.T) {
+		type Schema struct {
+			Name     string `tag:"env | get('name')"`
+			Optional bool   `tag:"env | has('optional')"`
+		}
+
+		parser := NewParser[Schema](WithStrict(), WithVariables(map[string]any{"prefix": "APP_"}))
+
+		schema, err := parser.ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Empty(t, schema.Name)
+		assert.False(t, schema.Optional)
+	})
+
+	t.Run("uses parser functions", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"env | get('name') | shout()"`
+		}
+
+		fns := NewFunctions()
+		assert.NoError(t, fns.Register("shout", fnShout, new(func(string) (string, error))))
+
+		schema, err := NewParser[Schema](WithFunctions(fns)).ParseTags(map[string]string{"env": "name=url"})
+		assert.NoError(t, err)
+		assert.Equal(t, "URL!", schema.Name)
+
+		schema, err = NewParser[Schema]().ParseTags(map[string]string{"env": "name=url"})
+		assert.NoError(t, err)
+		assert.Empty(t, schema.Name)
+	})
+
+	t.Run("exposes extra variables", func(t *testing.T) {
+		type Schema struct {
+			Name   string `tag:"prefix + get(env, 'name')"`
+			Shadow string `tag:"env"`
+		}
+
+		parser := NewParser[Schema](WithVariables(map[string]any{"prefix": "APP_", "env": "shadowed"}))
+
+		schema, err := parser.ParseTags(map[string]string{"env": "name=URL"})
+		assert.NoError(t, err)
+		assert.Equal(t, "APP_URL", schema.Name)
+		assert.Equal(t, "name=URL", schema.Shadow)
+	})
+
+	t.Run("parses without cache", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"json"`
+		}
+
+		schema, err := NewParser[Schema](WithCache(false)).ParseTags(map[string]string{"json": "name"})
+		assert.NoError(t, err)
+		assert.Equal(t, "name", schema.Name)
+	})
+}
+
+func TestParser_ParseStruct(t *testing.T) {
+	type Schema struct {
+		Name string `tag:"env | get('name')"`
+	}
+
+	t.Run("parses every field in order", func(t *testing.T) {
+		type Config struct {
+			Url  string `env:"name=URL"`
+			Port int    `env:"name=PORT"`
+			Host string
+		}
+
+		schemas, err := NewParser[Schema]().ParseStruct(&Config{})
+		assert.NoError(t, err)
+		assert.Len(t, schemas, 3)
+		assert.Equal(t, "URL", schemas[0].Name)
+		assert.Equal(t, "PORT", schemas[1].Name)
+		assert.Empty(t, schemas[2].Name)
+	})
+
+	t.Run("omits skipped fields", func(t *testing.T) {
+		type Config struct {
+			Url  string `env:"name=URL"`
+			Host string
+		}
+
+		schemas, err := NewParser[Schema](WithSkipUntagged()).ParseStruct(&Config{})
+		assert.NoError(t, err)
+		assert.Len(t, schemas, 1)
+		assert.Equal(t, "URL", schemas[0].Name)
+	})
+
+	t.Run("returns error when value is not a struct", func(t *testing.T) {
+		_, err := NewParser[Schema]().ParseStruct(123)
+		assert.ErrorIs(t, err, ErrNotAStruct)
+	})
+}
+
+func TestParser_AggregateErrors(t *testing.T) {
+	t.Run("returns every compile error", func(t *testing.T) {
+		type Schema struct {
+			A string `tag:"get(((("`
+			B string `tag:"json"`
+			C string `tag:"has(json"`
+		}
+
+		_, err := NewParser[Schema](WithAggregateErrors()).ParseTags(map[string]string{})
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 2)
+		assert.ErrorIs(t, err, ErrCompileTag)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, errs[0], &fieldErr)
+		assert.Equal(t, "A", fieldErr.FieldPath)
+		assert.ErrorAs(t, errs[1], &fieldErr)
+		assert.Equal(t, "C", fieldErr.FieldPath)
+	})
+
+	t.Run("returns only the first error by default", func(t *testing.T) {
+		type Schema struct {
+			A string `tag:"get(((("`
+			C string `tag:"has(json"`
+		}
+
+		_, err := NewParser[Schema]().ParseTags(map[string]string{})
+
+		var errs Errors
+		assert.False(t, errors.As(err, &errs))
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "A", fieldErr.FieldPath)
+	})
+
+	t.Run("returns every evaluation and conversion error", func(t *testing.T) {
+		type Schema struct {
+			Port    int    `tag:"env | get('port')"`
+			Index   string `tag:"split(env, ',')[9]"`
+			Timeout int    `tag:"env | get('timeout')"`
+		}
+
+		parser := NewParser[Schema](WithStrict(), WithAggregateErrors())
+
+		_, err := parser.ParseTags(map[string]string{"env": "port=http, timeout=soon"})
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 3)
+		assert.ErrorIs(t, errs[0], ErrCannotConvert)
+		assert.ErrorIs(t, errs[1], ErrEvalTag)
+		assert.ErrorIs(t, errs[2], ErrCannotConvert)
+	})
+
+	t.Run("returns every error of every field in ParseStruct", func(t *testing.T) {
+		type Schema struct {
+			Port int `tag:"env | get('port')"`
+		}
+		type Config struct {
+			Http  string `env:"port=http"`
+			Valid string `env:"port=8080"`
+			Https string `env:"port=https"`
+		}
+
+		_, err := NewParser[Schema](WithAggregateErrors()).ParseStruct(&Config{})
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 2)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, errs[0], &fieldErr)
+		assert.Equal(t, "Http", fieldErr.Source)
+		assert.ErrorAs(t, errs[1], &fieldErr)
+		assert.Equal(t, "Https", fieldErr.Source)
+	})
+
+	t.Run("returns malformed struct tags as field errors in ParseAll", func(t *testing.T) {
+		type Schema struct {
+			Port int `tag:"env | get('port')"`
+		}
+
+		// built at runtime, since go vet rejects malformed tags
+		typ := reflect.StructOf([]reflect.StructField{
+			{Name: "Http", Type: reflect.TypeFor[string](), Tag: `env:"port=http`},
+			{Name: "Valid", Type: reflect.TypeFor[string](), Tag: `env:"port=8080"`},
+		})
+
+		_, err := NewParser[Schema](WithAggregateErrors()).ParseAll(reflect.New(typ).Interface())
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], ErrMalformedTag)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, errs[0], &fieldErr)
+		assert.Equal(t, "Http", fieldErr.Source)
+	})
+}
+
+func TestParser_NestedSchemas(t *testing.T) {
+	t.Run("evaluates nested schema structs", func(t *testing.T) {
+		type ValidationSchema struct {
+			Required bool `tag:"has(validate, 'required')"`
+			Min      int  `tag:"validate | get('min')"`
+		}
+		type Schema struct {
+			Name       string `tag:"env | get('name')"`
+			Validation ValidationSchema
+			Optional   *ValidationSchema
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"env": "name=PORT", "validate": "required, min=2"})
+		assert.NoError(t, err)
+		assert.Equal(t, "PORT", schema.Name)
+		assert.True(t, schema.Validation.Required)
+		assert.Equal(t, 2, schema.Validation.Min)
+		assert.Equal(t, &ValidationSchema{Required: true, Min: 2}, schema.Optional)
+	})
+
+	t.Run("leaves nested pointers nil when nothing is set", func(t *testing.T) {
+		type ValidationSchema struct {
+			Min int `tag:"validate | get('min')"`
+		}
+		type Schema struct {
+			Validation *ValidationSchema
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Nil(t, schema.Validation)
+	})
+
+	t.Run("decodes map outputs into struct fields", func(t *testing.T) {
+		type Range struct {
+			Min int
+			Max int
+		}
+		type Schema struct {
+			Range Range  `tag:"{'min': validate | get('min'), 'max': validate | get('max')}"`
+			Ptr   *Range `tag:"{'min': validate | get('min')}"`
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"validate": "min=1, max=10"})
+		assert.NoError(t, err)
+		assert.Equal(t, Range{Min: 1, Max: 10}, schema.Range)
+		assert.Equal(t, &Range{Min: 1}, schema.Ptr)
+	})
+
+	t.Run("decodes list outputs into slice of struct fields", func(t *testing.T) {
+		type Rule struct {
+			Name  string
+			Value string
+		}
+		type Schema struct {
+			Rules []Rule `tag:"[{'name': 'min', 'value': validate | get('min')}, {'name': 'max', 'value': validate | get('max')}]"`
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"validate": "min=1, max=10"})
+		assert.NoError(t, err)
+		assert.Equal(t, []Rule{{"min", "1"}, {"max", "10"}}, schema.Rules)
+	})
+
+	t.Run("returns errors with the nested path", func(t *testing.T) {
+		type ValidationSchema struct {
+			Min int `tag:"validate | get('min')"`
+			Bad int `tag:"get(((("`
+		}
+		type Schema struct {
+			Validation ValidationSchema
+		}
+
+		_, err := NewParser[Schema]().ParseTags(map[string]string{})
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Validation.Bad", fieldErr.FieldPath)
+	})
+
+	t.Run("stops on recursive schemas", func(t *testing.T) {
+		type Node struct {
+			Name string `tag:"json"`
+			Next *Node
+		}
+
+		schema, err := NewParser[Node]().ParseTags(map[string]string{"json": "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, "a", schema.Name)
+		assert.Nil(t, schema.Next)
+	})
+}
+
+func TestParser_Defaults(t *testing.T) {
+	type Schema struct {
+		Port    int      `tag:"env | get('port')" tagdefault:"8080"`
+		Host    string   `tagdefault:"localhost"`
+		Verbose bool     `tag:"env | has('verbose')" tagdefault:"true"`
+		Oneof   []string `tag:"env | get('oneof') | split('|')" tagdefault:"a"`
+	}
+
+	t.Run("uses the default when the expression yields nothing", func(t *testing.T) {
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, schema.Port)
+		assert.Equal(t, "localhost", schema.Host)
+		assert.False(t, schema.Verbose)
+		assert.Equal(t, []string{"a"}, schema.Oneof)
+	})
+
+	t.Run("prefers the expression output", func(t *testing.T) {
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"env": "port=3000, oneof=b|c"})
+		assert.NoError(t, err)
+		assert.Equal(t, 3000, schema.Port)
+		assert.Equal(t, []string{"b", "c"}, schema.Oneof)
+	})
+
+	t.Run("uses the default when evaluation fails outside strict mode", func(t *testing.T) {
+		type Schema struct {
+			Port int `tag:"split(env, ',')[3]" tagdefault:"8080"`
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"env": "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, schema.Port)
+
+		_, err = NewParser[Schema](WithStrict()).ParseTags(map[string]string{"env": "a"})
+		assert.ErrorIs(t, err, ErrEvalTag)
+	})
+
+	t.Run("follows the tag name", func(t *testing.T) {
+		type Schema struct {
+			Port int `expr:"env | get('port')" exprdefault:"8080" tagdefault:"1"`
+		}
+
+		schema, err := NewParser[Schema](WithTagName("expr")).ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, schema.Port)
+	})
+
+	t.Run("returns an error for invalid defaults", func(t *testing.T) {
+		type Schema struct {
+			Port int `tag:"env | get('port')" tagdefault:"abc"`
+		}
+
+		_, err := NewParser[Schema]().ParseTags(map[string]string{})
+		assert.ErrorIs(t, err, ErrCannotConvert)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Port", fieldErr.FieldPath)
+		assert.Equal(t, "tagdefault", fieldErr.TagKey)
+	})
+}
+
+func TestParser_Required(t *testing.T) {
+	type Schema struct {
+		Name  string   `tag:"env | get('name')" tagrequired:"true"`
+		Oneof []string `tag:"env | get('oneof') | split('|')" tagrequired:"false"`
+	}
+
+	t.Run("passes when the expression yields a value", func(t *testing.T) {
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"env": "name=PORT"})
+		assert.NoError(t, err)
+		assert.Equal(t, "PORT", schema.Name)
+	})
+
+	t.Run("fails with the source field and tags when the expression yields nothing", func(t *testing.T) {
+		type Config struct {
+			Port int `env:"optional"`
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		_, err = NewParser[Schema]().Parse(inspector.Fields()[0])
+		assert.ErrorIs(t, err, ErrRequired)
+		assert.Contains(t, err.Error(), "tiq.Schema.Name (parsing field Port)")
+		assert.Contains(t, err.Error(), "no value found in tags env")
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "Name", fieldErr.FieldPath)
+		assert.Equal(t, "Port", fieldErr.Source)
+	})
+
+	t.Run("fails when the expression yields an empty value", func(t *testing.T) {
+		type Schema struct {
+			Name  string   `tag:"env | get('name')" tagrequired:"true"`
+			Flags []string `tag:"[]" tagrequired:"true"`
+		}
+
+		_, err := NewParser[Schema](WithAggregateErrors()).ParseTags(map[string]string{"env": "name="})
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 2)
+		assert.ErrorIs(t, err, ErrRequired)
+	})
+
+	t.Run("is satisfied by a default value", func(t *testing.T) {
+		type Schema struct {
+			Port int `tag:"env | get('port')" tagdefault:"8080" tagrequired:"true"`
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, schema.Port)
+	})
+
+	t.Run("returns an error for invalid required values", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"env | get('name')" tagrequired:"yes"`
+		}
+
+		_, err := NewParser[Schema]().ParseTags(map[string]string{"env": "name=PORT"})
+		assert.ErrorIs(t, err, ErrCompileTag)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "tagrequired", fieldErr.TagKey)
+	})
+}
+
+func TestParser_FieldMetadata(t *testing.T) {
+	type Schema struct {
+		Name     string `tag:"env | get('name') | default($field.name)"`
+		Type     string `tag:"$field.type"`
+		Kind     string `tag:"$field.kind"`
+		Index    int    `tag:"$field.index"`
+		Path     string `tag:"$field.path"`
+		Exported bool   `tag:"$field.exported"`
+	}
+
+	t.Run("exposes the parsed field as $field", func(t *testing.T) {
+		type Database struct {
+			Hosts []string
+		}
+		type Config struct {
+			Port     int `env:"name=APP_PORT"`
+			Database Database
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		schema, err := NewParser[Schema]().Parse(inspector.Fields()[0])
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{Name: "APP_PORT", Type: "int", Kind: "int", Index: 0, Path: "Port", Exported: true}, schema)
+
+		hosts, ok := inspector.Field("Database.Hosts")
+		assert.True(t, ok)
+
+		schema, err = NewParser[Schema]().Parse(hosts)
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{Name: "Hosts", Type: "[]string", Kind: "slice", Index: 0, Path: "Database.Hosts", Exported: true}, schema)
+	})
+
+	t.Run("is not a referenced tag", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"env | get('name') | default($field.name)" tagrequired:"true"`
+		}
+
+		_, err := NewParser[Schema]().ParseTags(map[string]string{})
+		assert.ErrorIs(t, err, ErrRequired)
+		assert.Contains(t, err.Error(), "no value found in tags env")
+	})
+
+	t.Run("is nil when parsing raw tags", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"$field?.name ?? 'none'"`
+		}
+
+		schema, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{})
+		assert.NoError(t, err)
+		assert.Equal(t, "none", schema.Name)
+	})
+}
+
+type keyedSchema struct {
+	Name string `tag:"env | get('name')"`
+}
+
+func (keyedSchema) TagKeys() []string { return []string{"env"} }
+
+func TestParser_TagKeys(t *testing.T) {
+	t.Run("parses declared tags", func(t *testing.T) {
+		type Schema struct {
+			Name   string `tag:"prefix + (env | get('name') ?? $field?.name ?? '')"`
+			Port   int    `tag:"env | get('port') | int()"`
+			Loaded bool   `tag:"has(json, 'x')"`
+		}
+
+		parser := NewParser[Schema](WithTagKeys("env", "json"), WithVariables(map[string]any{"prefix": "APP_"}), WithStrict())
+
+		schema, err := parser.ParseTags(map[string]string{"env": "name=PORT, port=80"})
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{Name: "APP_PORT", Port: 80}, schema)
+	})
+
+	t.Run("rejects unknown variables", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"evn | get('name')"`
+		}
+
+		_, err := NewParser[Schema](WithTagKeys("env")).ParseTags(map[string]string{})
+		assert.ErrorIs(t, err, ErrCompileTag)
+		assert.ErrorContains(t, err, "unknown name evn")
+
+		// without declared tags, typos are undefined variables evaluating to nil
+		_, err = NewParser[Schema]().ParseTags(map[string]string{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects mismatched function arguments", func(t *testing.T) {
+		type Schema struct {
+			Name string `tag:"nth(env, 'x')"`
+		}
+
+		_, err := NewParser[Schema](WithTagKeys("env")).ParseTags(map[string]string{})
+		assert.ErrorIs(t, err, ErrCompileTag)
+		assert.ErrorContains(t, err, "cannot use string as argument (type int) to call nth")
+	})
+
+	t.Run("rejects outputs not matching the schema field", func(t *testing.T) {
+		type Schema struct {
+			Flags []string `tag:"env | has('optional')"`
+		}
+
+		_, err := NewParser[Schema](WithTagKeys("env")).ParseTags(map[string]string{})
+		assert.ErrorIs(t, err, ErrTagType)
+	})
+
+	t.Run("uses tags declared by the schema", func(t *testing.T) {
+		schema, err := NewParser[keyedSchema]().ParseTags(map[string]string{"env": "name=PORT"})
+		assert.NoError(t, err)
+		assert.Equal(t, "PORT", schema.Name)
+
+		type Schema struct {
+			keyedSchema
+			Other string `tag:"json"`
+		}
+
+		// TagKeys is promoted from the embedded schema, which doesn't declare json
+		_, err = NewParser[Schema]().ParseTags(map[string]string{})
+		assert.ErrorContains(t, err, "unknown name json")
+	})
+}
@@ -0,0 +1,184 @@
+package tiq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/AnatoleLucet/as"
+)
+
+// pathSegment is a single dot separated part of a field path, like
+// `Servers[2]` which is made of the name "Servers" and the key "2".
+type pathSegment struct {
+	raw  string
+	name string
+	keys []string
+}
+
+// parsePath splits a field path such as "Database.Servers[2].Labels[env]" into its segments.
+func parsePath(path string) ([]pathSegment, error) {
+	segments := []pathSegment{}
+
+	for i := 0; i < len(path); {
+		start := i
+		segment := pathSegment{}
+
+		for i < len(path) && path[i] != '.' && path[i] != '[' {
+			i++
+		}
+		segment.name = path[start:i]
+		if segment.name == "" {
+			return nil, fmt.Errorf("%w: empty field name at offset %d", ErrInvalidPath, start)
+		}
+
+		for i < len(path) && path[i] == '[' {
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("%w: unclosed bracket at offset %d", ErrInvalidPath, i)
+			}
+
+			segment.keys = append(segment.keys, path[i+1:i+end])
+			i += end + 1
+		}
+
+		segment.raw = path[start:i]
+
+		if i < len(path) {
+			if path[i] != '.' {
+				return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidPath, path[i], i)
+			}
+			if i == len(path)-1 {
+				return nil, fmt.Errorf("%w: trailing dot", ErrInvalidPath)
+			}
+			i++
+		}
+
+		segments = append(segments, segment)
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidPath)
+	}
+
+	return segments, nil
+}
+
+// Lookup returns the field at the given path. Paths are made of dot separated
+// field names (e.g. "Database.Pool.MaxConns"), and can index slices, arrays
+// and maps with brackets (e.g. "Servers[2].Host" or "Labels[env]").
+// The returned error is a *FieldError telling which segment of the path
+// couldn't be resolved.
+func (i *Inspector) Lookup(path string) (*Field, error) {
+	return i.lookup(path, false)
+}
+
+// lookup resolves the given path. When create is true, a missing map key in
+// the last segment resolves to a field that will add the key once set.
+func (i *Inspector) lookup(path string, create bool) (*Field, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, newFieldError(i.value.Type(), path, err)
+	}
+
+	var field *Field
+	current := i.value
+
+	for n, segment := range segments {
+		fail := func(err error, format string, args ...any) error {
+			return newFieldError(i.value.Type(), path, fmt.Errorf("%w: segment %q: %s", err, segment.raw, fmt.Sprintf(format, args...)))
+		}
+
+		current, err = deref(current)
+		if err != nil {
+			return nil, fail(ErrFieldNotFound, "%v", err)
+		}
+		if current.Kind() != reflect.Struct {
+			return nil, fail(ErrFieldNotFound, "cannot access field %q of %s", segment.name, current.Type())
+		}
+
+		info := typeInfoOf(current.Type())
+		index, ok := info.names[segment.name]
+		if !ok {
+			return nil, fail(ErrFieldNotFound, "no field %q in %s", segment.name, current.Type())
+		}
+
+		prefix := ""
+		if field != nil {
+			prefix = field.path + "."
+		}
+		field = info.field(current, index, prefix)
+		field.path = prefix + segment.raw
+
+		for k, key := range segment.keys {
+			last := n == len(segments)-1 && k == len(segment.keys)-1
+
+			err = field.index(key, last && create)
+			if err != nil {
+				return nil, fail(ErrFieldNotFound, "%v", err)
+			}
+		}
+
+		current = field.Value
+	}
+
+	return field, nil
+}
+
+// index moves the field's value to the element at the given slice, array or
+// map key.
+func (f *Field) index(key string, create bool) error {
+	v, err := deref(f.Value)
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		n, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("invalid index %q for %s", key, v.Type())
+		}
+		if n < 0 || n >= v.Len() {
+			return fmt.Errorf("index %d out of range for %s of length %d", n, v.Type(), v.Len())
+		}
+
+		f.Value = v.Index(n)
+		f.mapValue = reflect.Value{}
+	case reflect.Map:
+		k, err := as.Type(v.Type().Key(), key)
+		if err != nil {
+			return fmt.Errorf("invalid key %q for %s: %v", key, v.Type(), err)
+		}
+		mapKey := reflect.ValueOf(k).Convert(v.Type().Key())
+
+		elem := v.MapIndex(mapKey)
+		if !elem.IsValid() {
+			if !create {
+				return fmt.Errorf("key %q not found in %s", key, v.Type())
+			}
+			elem = reflect.Zero(v.Type().Elem())
+		}
+
+		f.Value = elem
+		f.mapValue = v
+		f.mapKey = mapKey
+	default:
+		return fmt.Errorf("cannot index %s", v.Type())
+	}
+
+	return nil
+}
+
+// deref follows pointers and interfaces until it reaches a concrete value.
+func deref(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, fmt.Errorf("nil %s", v.Type())
+		}
+		v = v.Elem()
+	}
+
+	return v, nil
+}
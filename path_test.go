@@ -0,0 +1,208 @@
+package tiq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	t.Run("parses single field name", func(t *testing.T) {
+		segments, err := parsePath("Name")
+		assert.NoError(t, err)
+		assert.Equal(t, []pathSegment{{raw: "Name", name: "Name"}}, segments)
+	})
+
+	t.Run("parses dotted path", func(t *testing.T) {
+		segments, err := parsePath("Database.Pool.MaxConns")
+		assert.NoError(t, err)
+		assert.Len(t, segments, 3)
+		assert.Equal(t, "Database", segments[0].name)
+		assert.Equal(t, "Pool", segments[1].name)
+		assert.Equal(t, "MaxConns", segments[2].name)
+	})
+
+	t.Run("parses bracket keys", func(t *testing.T) {
+		segments, err := parsePath("Servers[2].Labels[app.env]")
+		assert.NoError(t, err)
+		assert.Equal(t, []pathSegment{
+			{raw: "Servers[2]", name: "Servers", keys: []string{"2"}},
+			{raw: "Labels[app.env]", name: "Labels", keys: []string{"app.env"}},
+		}, segments)
+	})
+
+	t.Run("parses consecutive bracket keys", func(t *testing.T) {
+		segments, err := parsePath("Matrix[1][0]")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "0"}, segments[0].keys)
+	})
+
+	t.Run("returns error for invalid paths", func(t *testing.T) {
+		for _, path := range []string{"", ".Name", "Name.", "Database..Host", "Servers[2", "Servers[2]Host", "[2]"} {
+			_, err := parsePath(path)
+			assert.ErrorIs(t, err, ErrInvalidPath, path)
+		}
+	})
+}
+
+func TestInspector_Lookup(t *testing.T) {
+	type Server struct {
+		Host string `json:"host"`
+	}
+	type Pool struct {
+		MaxConns int `env:"name=MAX_CONNS"`
+	}
+	type Database struct {
+		Pool *Pool
+	}
+	type Config struct {
+		Database Database
+		Servers  []Server
+		Backups  [2]Server
+		Labels   map[string]string `json:"labels"`
+		Ports    map[int]string
+		Named    map[string]*Server
+	}
+
+	config := Config{
+		Database: Database{Pool: &Pool{MaxConns: 10}},
+		Servers:  []Server{{Host: "a"}, {Host: "b"}},
+		Labels:   map[string]string{"env": "prod"},
+		Ports:    map[int]string{80: "http"},
+		Named:    map[string]*Server{"main": {Host: "c"}},
+	}
+
+	t.Run("resolves nested struct fields through pointers", func(t *testing.T) {
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		field, err := inspector.Lookup("Database.Pool.MaxConns")
+		assert.NoError(t, err)
+		assert.Equal(t, "Database.Pool.MaxConns", field.Path())
+		assert.Equal(t, 10, field.Interface())
+
+		tag, ok := field.Tag("env")
+		assert.True(t, ok)
+		assert.Equal(t, "name=MAX_CONNS", tag)
+	})
+
+	t.Run("resolves slice and array elements", func(t *testing.T) {
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		field, err := inspector.Lookup("Servers[1].Host")
+		assert.NoError(t, err)
+		assert.Equal(t, "b", field.Interface())
+		assert.Equal(t, "Servers[1].Host", field.Path())
+
+		field, err = inspector.Lookup("Backups[0].Host")
+		assert.NoError(t, err)
+		assert.Equal(t, "", field.Interface())
+	})
+
+	t.Run("resolves map entries", func(t *testing.T) {
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		field, err := inspector.Lookup("Labels[env]")
+		assert.NoError(t, err)
+		assert.Equal(t, "prod", field.Interface())
+
+		tag, ok := field.Tag("json")
+		assert.True(t, ok)
+		assert.Equal(t, "labels", tag)
+
+		field, err = inspector.Lookup("Ports[80]")
+		assert.NoError(t, err)
+		assert.Equal(t, "http", field.Interface())
+
+		field, err = inspector.Lookup("Named[main].Host")
+		assert.NoError(t, err)
+		assert.Equal(t, "c", field.Interface())
+	})
+
+	t.Run("returns error naming the failing segment", func(t *testing.T) {
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		tests := map[string]string{
+			"Database.Missing.MaxConns": `tiq.Config.Database.Missing.MaxConns: field not found: segment "Missing"`,
+			"Servers[5].Host":           `segment "Servers[5]": index 5 out of range`,
+			"Servers[x].Host":           `invalid index "x"`,
+			"Labels[missing]":           `key "missing" not found`,
+			"Ports[http]":               `invalid key "http"`,
+			"Database.Pool.MaxConns[0]": `cannot index int`,
+			"Servers.Host":              `cannot access field "Host"`,
+		}
+
+		for path, message := range tests {
+			_, err := inspector.Lookup(path)
+			assert.ErrorIs(t, err, ErrFieldNotFound, path)
+			assert.ErrorContains(t, err, message, path)
+
+			var fieldErr *FieldError
+			assert.ErrorAs(t, err, &fieldErr)
+			assert.Equal(t, path, fieldErr.FieldPath)
+		}
+	})
+
+	t.Run("returns error when traversing a nil pointer", func(t *testing.T) {
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		_, err = inspector.Lookup("Database.Pool.MaxConns")
+		assert.ErrorIs(t, err, ErrFieldNotFound)
+		assert.ErrorContains(t, err, `segment "MaxConns"`)
+		assert.ErrorContains(t, err, "nil *tiq.Pool")
+	})
+
+	t.Run("returns error for invalid path", func(t *testing.T) {
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		_, err = inspector.Lookup("Servers[1")
+		assert.ErrorIs(t, err, ErrInvalidPath)
+	})
+}
+
+func TestField_SetMapEntry(t *testing.T) {
+	type Config struct {
+		Labels map[string]string
+	}
+
+	t.Run("sets existing and new map entries", func(t *testing.T) {
+		config := Config{Labels: map[string]string{"env": "dev"}}
+
+		assert.NoError(t, Set(&config, "Labels[env]", "prod"))
+		assert.NoError(t, Set(&config, "Labels[app]", "api"))
+		assert.Equal(t, map[string]string{"env": "prod", "app": "api"}, config.Labels)
+	})
+
+	t.Run("allocates nil maps", func(t *testing.T) {
+		config := Config{}
+
+		assert.NoError(t, Set(&config, "Labels[env]", "prod"))
+		assert.Equal(t, map[string]string{"env": "prod"}, config.Labels)
+	})
+
+	t.Run("converts values with SetFrom", func(t *testing.T) {
+		type Limits struct {
+			Values map[string]int
+		}
+
+		limits := Limits{}
+		inspector, err := Inspect(&limits)
+		assert.NoError(t, err)
+
+		field, err := inspector.lookup("Values[max]", true)
+		assert.NoError(t, err)
+
+		assert.NoError(t, field.SetFrom("42"))
+		assert.Equal(t, 42, limits.Values["max"])
+	})
+
+	t.Run("returns error when map is not settable", func(t *testing.T) {
+		err := Set(Config{Labels: map[string]string{}}, "Labels[env]", "prod")
+		assert.ErrorIs(t, err, ErrFieldNotSettable)
+	})
+}
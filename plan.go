@@ -0,0 +1,309 @@
+package tiq
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+)
+
+// plan holds the compiled expressions of a schema type, so they only need to
+// be compiled once and can be shared across goroutines.
+type plan struct {
+	schema reflect.Type
+	fields []planField
+
+	// tags lists every variable referenced by the schema's expressions.
+	tags []string
+}
+
+type planField struct {
+	field      reflect.StructField
+	index      []int  // index of the field from the schema's root
+	path       string // dot separated path of the field from the schema's root
+	expression string
+	program    *vm.Program // nil if the field only has a default value
+	tags       []string
+
+	defaultValue string
+	hasDefault   bool
+	required     bool
+}
+
+// planKey identifies a plan by schema type and the options affecting compilation.
+type planKey struct {
+	schema  reflect.Type
+	tagName string
+	env     string // see config.envKey
+}
+
+// plans caches every plan compiled without a function registry by planKey.
+// Plans using a registry are cached on it instead, so they are released along
+// with the registry.
+var plans sync.Map // map[planKey]*plan
+
+// planFor returns the plan of the given schema type, compiling it on first use.
+func planFor(schema reflect.Type, c *config) (*plan, error) {
+	if !c.cache {
+		return newPlan(schema, c)
+	}
+
+	cache := &plans
+	if c.functions != nil {
+		cache = &c.functions.plans
+	}
+
+	key := planKey{schema, c.tagName, c.envKey(schema)}
+	if p, ok := cache.Load(key); ok {
+		return p.(*plan), nil
+	}
+
+	p, err := newPlan(schema, c)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := cache.LoadOrStore(key, p)
+	return actual.(*plan), nil
+}
+
+// envKeyOf identifies the options a schema's compile environment depends on.
+type envKeyOf struct {
+	schema     reflect.Type
+	tagKeys    string // space-separated, as tag keys can't contain spaces
+	hasTagKeys bool
+	variables  string // see describeEnv
+}
+
+// envKeys caches the envKey of every schema per tag keys and variables, so
+// parsers created on every call (e.g. by tiq.Parse) don't recompute it.
+var envKeys sync.Map // map[envKeyOf]string
+
+// envKey returns a comparable description of the schema's compile environment.
+func (c *config) envKey(schema reflect.Type) string {
+	memo := envKeyOf{schema, strings.Join(c.tagKeys, " "), c.tagKeys != nil, describeEnv(c.variables)}
+	if key, ok := envKeys.Load(memo); ok {
+		return key.(string)
+	}
+
+	key := describeEnv(c.env(schema))
+	envKeys.Store(memo, key)
+	return key
+}
+
+// describeEnv returns the sorted names and types of the given variables.
+func describeEnv(env map[string]any) string {
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		fmt.Fprintf(&b, "%s:%T;", name, env[name])
+	}
+
+	return b.String()
+}
+
+func newPlan(schema reflect.Type, c *config) (*plan, error) {
+	if schema.Kind() != reflect.Struct {
+		return nil, ErrNotAStruct
+	}
+
+	b := &planBuilder{
+		config:   c,
+		env:      c.env(schema),
+		plan:     &plan{schema: schema},
+		visiting: map[reflect.Type]bool{schema: true},
+	}
+	if c.functions != nil {
+		b.functions = append(b.functions, c.functions)
+	}
+
+	b.add(schema, nil, "")
+
+	if len(b.errs) > 0 {
+		return nil, c.err(b.errs)
+	}
+
+	return b.plan, nil
+}
+
+type planBuilder struct {
+	config    *config
+	env       map[string]any // nil if expressions are not type checked
+	functions []*Functions
+	plan      *plan
+	errs      Errors
+
+	// visiting holds the struct types being added, to stop on recursive schemas.
+	visiting map[reflect.Type]bool
+}
+
+// add compiles every tagged field of the given struct type, and recursively
+// adds the fields of untagged nested structs.
+func (b *planBuilder) add(typ reflect.Type, index []int, prefix string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		path := prefix + field.Name
+		fieldIndex := append(slices.Clone(index), i)
+
+		expression, hasExpression := field.Tag.Lookup(b.config.tagName)
+		defaultValue, hasDefault := field.Tag.Lookup(b.config.defaultTagName())
+		required, hasRequired := field.Tag.Lookup(b.config.requiredTagName())
+
+		if !hasExpression && !hasDefault && !hasRequired {
+			nested, ok := nestedSchema(field)
+			if ok && !b.visiting[nested] {
+				b.visiting[nested] = true
+				b.add(nested, fieldIndex, path+".")
+				delete(b.visiting, nested)
+			}
+			continue
+		}
+
+		pf := planField{
+			field:        field,
+			index:        fieldIndex,
+			path:         path,
+			expression:   expression,
+			defaultValue: defaultValue,
+			hasDefault:   hasDefault,
+		}
+
+		if hasRequired {
+			var err error
+			pf.required, err = strconv.ParseBool(required)
+			if err != nil {
+				e := newFieldError(b.plan.schema, path, fmt.Errorf("%w: %q is not a boolean", ErrCompileTag, required))
+				e.TagKey = b.config.requiredTagName()
+
+				b.errs = append(b.errs, e)
+				continue
+			}
+		}
+
+		if hasDefault {
+			// convert the default once to report invalid defaults early
+			f := &Field{Value: reflect.New(field.Type).Elem(), StructField: field, path: path}
+			err := f.SetFrom(defaultValue)
+			if err != nil {
+				e := newFieldError(b.plan.schema, path, fmt.Errorf("invalid default %q: %w", defaultValue, err))
+				e.TagKey = b.config.defaultTagName()
+
+				b.errs = append(b.errs, e)
+				continue
+			}
+		}
+
+		if hasExpression {
+			program, err := compile(expression, b.env, b.functions...)
+			if err != nil {
+				e := newFieldError(b.plan.schema, path, err)
+				e.TagKey = b.config.tagName
+				e.Expression = expression
+
+				b.errs = append(b.errs, e)
+				continue
+			}
+
+			if b.config.typeCheck || b.env != nil {
+				err := checkType(program.Node().Type(), field.Type)
+				if err != nil {
+					e := newFieldError(b.plan.schema, path, err)
+					e.TagKey = b.config.tagName
+					e.Expression = expression
+
+					b.errs = append(b.errs, e)
+					continue
+				}
+			}
+
+			pf.program = program
+			pf.tags = slices.DeleteFunc(identifiers(program.Node()), func(name string) bool {
+				return name == fieldVariable
+			})
+		}
+
+		for _, tag := range pf.tags {
+			if !slices.Contains(b.plan.tags, tag) {
+				b.plan.tags = append(b.plan.tags, tag)
+			}
+		}
+
+		b.plan.fields = append(b.plan.fields, pf)
+	}
+}
+
+// nestedSchema returns the struct type of the given field if it can hold
+// nested schema fields.
+func nestedSchema(field reflect.StructField) (reflect.Type, bool) {
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+		return nil, false
+	}
+
+	return typ, true
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but allocates nil pointers
+// to structs along the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// identifiers returns the name of every variable referenced in the given
+// expression tree, ignoring function names and `let` variables.
+func identifiers(node ast.Node) []string {
+	v := &identifierVisitor{callees: map[ast.Node]bool{}, declared: map[string]bool{}}
+	ast.Walk(&node, v)
+
+	names := []string{}
+	for _, ident := range v.idents {
+		if v.callees[ident] || v.declared[ident.Value] || slices.Contains(names, ident.Value) {
+			continue
+		}
+
+		names = append(names, ident.Value)
+	}
+
+	return names
+}
+
+type identifierVisitor struct {
+	idents   []*ast.IdentifierNode
+	callees  map[ast.Node]bool
+	declared map[string]bool // names of `let` variables
+}
+
+func (v *identifierVisitor) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.IdentifierNode:
+		v.idents = append(v.idents, n)
+	case *ast.CallNode:
+		v.callees[n.Callee] = true
+	case *ast.VariableDeclaratorNode:
+		v.declared[n.Name] = true
+	}
+}
@@ -0,0 +1,210 @@
+package tiq
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanFor(t *testing.T) {
+	type Schema struct {
+		Name     string `tag:"json"`
+		Table    string `tag:"get(db, 'table')"`
+		Ignored  string
+		Required bool `tag:"has(validate, 'required')"`
+	}
+
+	t.Run("compiles every tagged schema field", func(t *testing.T) {
+		p, err := planFor(reflect.TypeFor[Schema](), newConfig())
+		assert.NoError(t, err)
+		assert.Len(t, p.fields, 3)
+		assert.Equal(t, "Name", p.fields[0].field.Name)
+		assert.Equal(t, "get(db, 'table')", p.fields[1].expression)
+		assert.Equal(t, "Required", p.fields[2].field.Name)
+		assert.Equal(t, reflect.TypeFor[bool](), p.fields[2].field.Type)
+	})
+
+	t.Run("returns the same plan on subsequent calls", func(t *testing.T) {
+		first, err := planFor(reflect.TypeFor[Schema](), newConfig())
+		assert.NoError(t, err)
+
+		second, err := planFor(reflect.TypeFor[Schema](), newConfig())
+		assert.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Same(t, first.fields[0].program, second.fields[0].program)
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		type ConcurrentSchema struct {
+			Name string `tag:"json"`
+		}
+
+		var wg sync.WaitGroup
+		results := make([]*plan, 16)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = planFor(reflect.TypeFor[ConcurrentSchema](), newConfig())
+			}()
+		}
+		wg.Wait()
+
+		for _, p := range results {
+			assert.Same(t, results[0], p)
+		}
+	})
+
+	t.Run("does not cache schemas that fail to compile", func(t *testing.T) {
+		type BadSchema struct {
+			Field string `tag:"invalid((("`
+		}
+
+		_, err := planFor(reflect.TypeFor[BadSchema](), newConfig())
+		assert.ErrorIs(t, err, ErrCompileTag)
+
+		_, ok := plans.Load(planKey{reflect.TypeFor[BadSchema](), "tag", ""})
+		assert.False(t, ok)
+	})
+
+	t.Run("caches plans separately per tag name", func(t *testing.T) {
+		type MultiSchema struct {
+			Name string `tag:"json" alt:"db"`
+		}
+
+		first, err := planFor(reflect.TypeFor[MultiSchema](), newConfig())
+		assert.NoError(t, err)
+
+		second, err := planFor(reflect.TypeFor[MultiSchema](), newConfig(WithTagName("alt")))
+		assert.NoError(t, err)
+
+		assert.NotSame(t, first, second)
+		assert.Equal(t, "json", first.fields[0].expression)
+		assert.Equal(t, "db", second.fields[0].expression)
+	})
+
+	t.Run("caches plans separately per declared tags", func(t *testing.T) {
+		type KeyedSchema struct {
+			Name string `tag:"json"`
+		}
+
+		first, err := planFor(reflect.TypeFor[KeyedSchema](), newConfig())
+		assert.NoError(t, err)
+
+		second, err := planFor(reflect.TypeFor[KeyedSchema](), newConfig(WithTagKeys("json")))
+		assert.NoError(t, err)
+		assert.NotSame(t, first, second)
+
+		_, err = planFor(reflect.TypeFor[KeyedSchema](), newConfig(WithTagKeys("env")))
+		assert.ErrorIs(t, err, ErrCompileTag)
+
+		third, err := planFor(reflect.TypeFor[KeyedSchema](), newConfig(WithTagKeys("json")))
+		assert.NoError(t, err)
+		assert.Same(t, second, third)
+	})
+
+	t.Run("shares environment keys between configs", func(t *testing.T) {
+		type EnvSchema struct {
+			Name string `tag:"json"`
+		}
+
+		_, err := planFor(reflect.TypeFor[EnvSchema](), newConfig(WithTagKeys("json", "db")))
+		assert.NoError(t, err)
+
+		key, ok := envKeys.Load(envKeyOf{reflect.TypeFor[EnvSchema](), "json db", true, ""})
+		assert.True(t, ok)
+		assert.Equal(t, newConfig(WithTagKeys("db", "json")).envKey(reflect.TypeFor[EnvSchema]()), key)
+
+		_, ok = envKeys.Load(envKeyOf{reflect.TypeFor[EnvSchema](), "", true, ""})
+		assert.False(t, ok)
+	})
+
+	t.Run("caches plans on function registries", func(t *testing.T) {
+		type FunctionsSchema struct {
+			Name string `tag:"json"`
+		}
+
+		fns := NewFunctions()
+
+		first, err := planFor(reflect.TypeFor[FunctionsSchema](), newConfig(WithFunctions(fns)))
+		assert.NoError(t, err)
+
+		second, err := planFor(reflect.TypeFor[FunctionsSchema](), newConfig(WithFunctions(fns)))
+		assert.NoError(t, err)
+		assert.Same(t, first, second)
+
+		other, err := planFor(reflect.TypeFor[FunctionsSchema](), newConfig(WithFunctions(NewFunctions())))
+		assert.NoError(t, err)
+		assert.NotSame(t, first, other)
+
+		_, ok := fns.plans.Load(planKey{reflect.TypeFor[FunctionsSchema](), "tag", ""})
+		assert.True(t, ok)
+
+		_, ok = plans.Load(planKey{reflect.TypeFor[FunctionsSchema](), "tag", ""})
+		assert.False(t, ok)
+	})
+
+	t.Run("does not cache when disabled", func(t *testing.T) {
+		type UncachedSchema struct {
+			Name string `tag:"json"`
+		}
+
+		_, err := planFor(reflect.TypeFor[UncachedSchema](), newConfig(WithCache(false)))
+		assert.NoError(t, err)
+
+		_, ok := plans.Load(planKey{reflect.TypeFor[UncachedSchema](), "tag", ""})
+		assert.False(t, ok)
+	})
+
+	t.Run("returns error when schema is not a struct", func(t *testing.T) {
+		_, err := planFor(reflect.TypeFor[string](), newConfig())
+		assert.ErrorIs(t, err, ErrNotAStruct)
+	})
+}
+
+type benchSchema struct {
+	Name     string   `tag:"env | get('name')"`
+	Type     string   `tag:"env | get('type')"`
+	Optional bool     `tag:"env | has('optional')"`
+	Oneof    []string `tag:"env | get('oneof') | split('|')"`
+}
+
+var benchTags = map[string]string{
+	"env": "name=PORT, type=port, optional, oneof=8080|3000|5000",
+}
+
+func BenchmarkParseTags(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = parseTags[benchSchema](benchTags)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			plans.Clear()
+			_, _ = parseTags[benchSchema](benchTags)
+		}
+	})
+}
+
+func TestIdentifiers(t *testing.T) {
+	tests := map[string][]string{
+		"json":                                 {"json"},
+		"env | get('name') | default(json)":    {"env", "json"},
+		"get(db, 'table') + get(db, 'column')": {"db"},
+		"has(validate, 'required') && !ok":     {"validate", "ok"},
+		"map(split(env, ','), # + suffix)":     {"env", "suffix"},
+		"'constant'":                           {},
+		"let n = get(env, 'name'); n + suffix": {"env", "suffix"},
+		"get(name, 'x') + upper(len)":          {"name", "len"},
+	}
+
+	for expression, expected := range tests {
+		program, err := compile(expression, nil)
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, identifiers(program.Node()), expression)
+	}
+}
@@ -0,0 +1,218 @@
+package tiq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ordinals = []string{"first", "second", "third"}
+
+// stringArgs checks that args holds exactly n strings and returns them.
+// It returns nil without error if the first argument is nil (e.g. a missing tag).
+func stringArgs(name string, args []any, n int) ([]string, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("%s() requires exactly %d arguments", name, n)
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	strs := make([]string, n)
+	for i, arg := range args {
+		str, ok := arg.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s() %s argument must be a string", name, ordinals[i])
+		}
+		strs[i] = str
+	}
+
+	return strs, nil
+}
+
+// stringFunction returns a DSL function applying fn to its only string argument.
+func stringFunction(name string, fn func(string) string) func(args ...any) (any, error) {
+	return func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s() requires exactly 1 argument", name)
+		}
+
+		if args[0] == nil {
+			return nil, nil
+		}
+
+		str, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s() argument must be a string", name)
+		}
+
+		return fn(str), nil
+	}
+}
+
+var (
+	fnUpper          = stringFunction("upper", strings.ToUpper)
+	fnLower          = stringFunction("lower", strings.ToLower)
+	fnSnake          = stringFunction("snake", snake)
+	fnCamel          = stringFunction("camel", camel)
+	fnKebab          = stringFunction("kebab", kebab)
+	fnScreamingSnake = stringFunction("screamingSnake", screamingSnake)
+)
+
+func fnTrim(args ...any) (any, error) {
+	if len(args) == 1 {
+		return stringFunction("trim", strings.TrimSpace)(args...)
+	}
+
+	strs, err := stringArgs("trim", args, 2)
+	if err != nil || strs == nil {
+		return nil, err
+	}
+
+	return strings.Trim(strs[0], strs[1]), nil
+}
+
+func fnReplace(args ...any) (any, error) {
+	strs, err := stringArgs("replace", args, 3)
+	if err != nil || strs == nil {
+		return nil, err
+	}
+
+	return strings.ReplaceAll(strs[0], strs[1], strs[2]), nil
+}
+
+func fnHasPrefix(args ...any) (any, error) {
+	strs, err := stringArgs("hasPrefix", args, 2)
+	if err != nil || strs == nil {
+		return false, err
+	}
+
+	return strings.HasPrefix(strs[0], strs[1]), nil
+}
+
+func fnHasSuffix(args ...any) (any, error) {
+	strs, err := stringArgs("hasSuffix", args, 2)
+	if err != nil || strs == nil {
+		return false, err
+	}
+
+	return strings.HasSuffix(strs[0], strs[1]), nil
+}
+
+func fnTrimPrefix(args ...any) (any, error) {
+	strs, err := stringArgs("trimPrefix", args, 2)
+	if err != nil || strs == nil {
+		return nil, err
+	}
+
+	return strings.TrimPrefix(strs[0], strs[1]), nil
+}
+
+func fnTrimSuffix(args ...any) (any, error) {
+	strs, err := stringArgs("trimSuffix", args, 2)
+	if err != nil || strs == nil {
+		return nil, err
+	}
+
+	return strings.TrimSuffix(strs[0], strs[1]), nil
+}
+
+func fnJoin(args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("join() requires exactly 2 arguments")
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	sep, ok := args[1].(string)
+	if !ok {
+		return nil, errors.New("join() second argument must be a string")
+	}
+
+	switch list := args[0].(type) {
+	case []string:
+		return strings.Join(list, sep), nil
+	case []any:
+		strs := make([]string, len(list))
+		for i, item := range list {
+			str, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("join() first argument must be a list of strings, got %T at index %d", item, i)
+			}
+			strs[i] = str
+		}
+		return strings.Join(strs, sep), nil
+	}
+
+	return nil, errors.New("join() first argument must be a list of strings")
+}
+
+// words splits a string into words on non-alphanumeric characters and case
+// changes (e.g. "HTTPServer_port" gives [HTTP Server port]).
+func words(s string) []string {
+	runes := []rune(s)
+	words := []string{}
+
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// "fooBar", "foo1Bar" or the end of an acronym in "HTTPServer"
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+func snake(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+func kebab(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+func screamingSnake(s string) string {
+	return strings.ToUpper(strings.Join(words(s), "_"))
+}
+
+func camel(s string) string {
+	var b strings.Builder
+	for i, word := range words(s) {
+		word = strings.ToLower(word)
+		if i > 0 {
+			r := []rune(word)
+			r[0] = unicode.ToUpper(r[0])
+			word = string(r)
+		}
+		b.WriteString(word)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package tiq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringFunctions(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     func(args ...any) (any, error)
+		args   []any
+		result any
+	}{
+		{"upper", fnUpper, []any{"foo"}, "FOO"},
+		{"lower", fnLower, []any{"FOO"}, "foo"},
+		{"trim", fnTrim, []any{"  foo \t"}, "foo"},
+		{"trim with cutset", fnTrim, []any{"--foo-", "-"}, "foo"},
+		{"replace", fnReplace, []any{"a.b.c", ".", "_"}, "a_b_c"},
+		{"hasPrefix", fnHasPrefix, []any{"APP_PORT", "APP_"}, true},
+		{"hasSuffix", fnHasSuffix, []any{"APP_PORT", "APP_"}, false},
+		{"trimPrefix", fnTrimPrefix, []any{"APP_PORT", "APP_"}, "PORT"},
+		{"trimSuffix", fnTrimSuffix, []any{"port.txt", ".txt"}, "port"},
+		{"join", fnJoin, []any{[]string{"a", "b"}, "|"}, "a|b"},
+		{"join any list", fnJoin, []any{[]any{"a", "b"}, ","}, "a,b"},
+		{"snake", fnSnake, []any{"MaxConns"}, "max_conns"},
+		{"camel", fnCamel, []any{"max_conns"}, "maxConns"},
+		{"kebab", fnKebab, []any{"MaxConns"}, "max-conns"},
+		{"screamingSnake", fnScreamingSnake, []any{"MaxConns"}, "MAX_CONNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.fn(tt.args...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		for _, fn := range []func(args ...any) (any, error){fnUpper, fnTrim, fnSnake} {
+			result, err := fn(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+		}
+
+		result, err := fnReplace(nil, "a", "b")
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+
+		result, err = fnJoin(nil, ",")
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+
+		result, err = fnHasPrefix(nil, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, false, result)
+	})
+
+	t.Run("returns error on wrong argument count", func(t *testing.T) {
+		_, err := fnUpper("a", "b")
+		assert.EqualError(t, err, "upper() requires exactly 1 argument")
+
+		_, err = fnReplace("a", "b")
+		assert.EqualError(t, err, "replace() requires exactly 3 arguments")
+
+		_, err = fnTrim("a", "b", "c")
+		assert.EqualError(t, err, "trim() requires exactly 2 arguments")
+	})
+
+	t.Run("returns error on wrong argument type", func(t *testing.T) {
+		_, err := fnLower(1)
+		assert.EqualError(t, err, "lower() argument must be a string")
+
+		_, err = fnReplace("a", "b", 1)
+		assert.EqualError(t, err, "replace() third argument must be a string")
+
+		_, err = fnJoin("a", ",")
+		assert.EqualError(t, err, "join() first argument must be a list of strings")
+
+		_, err = fnJoin([]any{"a", 1}, ",")
+		assert.EqualError(t, err, "join() first argument must be a list of strings, got int at index 1")
+	})
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		input string
+		words []string
+	}{
+		{"", []string{}},
+		{"foo", []string{"foo"}},
+		{"fooBar", []string{"foo", "Bar"}},
+		{"FooBar", []string{"Foo", "Bar"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"UserID", []string{"User", "ID"}},
+		{"Port8080", []string{"Port8080"}},
+		{"v2Api", []string{"v2", "Api"}},
+		{"foo_bar-baz qux", []string{"foo", "bar", "baz", "qux"}},
+		{"__foo__", []string{"foo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.words, words(tt.input))
+		})
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		input          string
+		snake          string
+		camel          string
+		kebab          string
+		screamingSnake string
+	}{
+		{"Name", "name", "name", "name", "NAME"},
+		{"MaxConns", "max_conns", "maxConns", "max-conns", "MAX_CONNS"},
+		{"DatabaseURL", "database_url", "databaseUrl", "database-url", "DATABASE_URL"},
+		{"HTTPServer", "http_server", "httpServer", "http-server", "HTTP_SERVER"},
+		{"max-conns", "max_conns", "maxConns", "max-conns", "MAX_CONNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.snake, snake(tt.input))
+			assert.Equal(t, tt.camel, camel(tt.input))
+			assert.Equal(t, tt.kebab, kebab(tt.input))
+			assert.Equal(t, tt.screamingSnake, screamingSnake(tt.input))
+		})
+	}
+}
+
+func TestStringFunctions_Expressions(t *testing.T) {
+	type Schema struct {
+		Name   string `tag:"env | get('name') | default(screamingSnake($field.name))"`
+		Prefix bool   `tag:"env | get('name') | hasPrefix('APP_')"`
+		Oneof  string `tag:"env | get('oneof') | split('|') | join(', ')"`
+		Upper  string `tag:"upper(trim(json))"`
+	}
+	type Config struct {
+		MaxConns int `env:"oneof=a|b" json:" conns "`
+	}
+
+	inspector, err := Inspect(&Config{})
+	assert.NoError(t, err)
+
+	schema, err := NewParser[Schema](WithStrict()).Parse(inspector.Fields()[0])
+	assert.NoError(t, err)
+	assert.Equal(t, &Schema{Name: "MAX_CONNS", Prefix: false, Oneof: "a, b", Upper: "CONNS"}, schema)
+}
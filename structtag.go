@@ -0,0 +1,92 @@
+package tiq
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Tag is a single key:"value" pair of a struct tag.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// lexStructTag splits a struct tag into its key:"value" pairs, in declared
+// order. It follows the grammar of reflect.StructTag: pairs are separated by
+// spaces, keys are non-empty runs of non-control characters other than space,
+// colon and quote, and values are Go double-quoted strings.
+//
+// When the tag is malformed, the pairs preceding the malformed part are
+// returned along with an error wrapping ErrMalformedTag.
+func lexStructTag(structTag reflect.StructTag) ([]Tag, error) {
+	tag := string(structTag)
+	tags := []Tag{}
+
+	offset := 0
+	malformed := func(format string, args ...any) ([]Tag, error) {
+		return tags, fmt.Errorf("%w: %s at offset %d", ErrMalformedTag, fmt.Sprintf(format, args...), offset)
+	}
+
+	for {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag, offset = tag[i:], offset+i
+		if tag == "" {
+			return tags, nil
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return malformed("missing key")
+		}
+		if i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return malformed("key %q is not followed by a quoted value", tag[:i])
+		}
+
+		key := tag[:i]
+		tag, offset = tag[i+1:], offset+i+1
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return malformed("unterminated value of key %q", key)
+		}
+
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return malformed("invalid value of key %q", key)
+		}
+
+		tags = append(tags, Tag{Key: key, Value: value})
+		tag, offset = tag[i+1:], offset+i+1
+	}
+}
+
+// structTags returns the tags of a struct tag as a map. Like
+// reflect.StructTag.Lookup, the first occurrence of a repeated key wins.
+func structTags(structTag reflect.StructTag) (map[string]string, error) {
+	ordered, err := lexStructTag(structTag)
+	return tagMap(ordered), err
+}
+
+func tagMap(ordered []Tag) map[string]string {
+	tags := make(map[string]string, len(ordered))
+	for _, t := range ordered {
+		if _, ok := tags[t.Key]; !ok {
+			tags[t.Key] = t.Value
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,84 @@
+package tiq
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexStructTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  reflect.StructTag
+		tags []Tag
+	}{
+		{"empty", ``, []Tag{}},
+		{"single", `json:"name"`, []Tag{{"json", "name"}}},
+		{"keeps declared order", `yaml:"b" json:"a" env:"c"`, []Tag{{"yaml", "b"}, {"json", "a"}, {"env", "c"}}},
+		{"values with spaces", `doc:"the user name" env:"name=X"`, []Tag{{"doc", "the user name"}, {"env", "name=X"}}},
+		{"values with key-like text", `doc:"see env:\"x\" below" json:"a"`, []Tag{{"doc", `see env:"x" below`}, {"json", "a"}}},
+		{"escapes", `doc:"tab\there \\ é"`, []Tag{{"doc", "tab\there \\ é"}}},
+		{"empty value", `json:""`, []Tag{{"json", ""}}},
+		{"extra spaces", `  json:"a"   env:"b"  `, []Tag{{"json", "a"}, {"env", "b"}}},
+		{"repeated keys", `json:"a" json:"b"`, []Tag{{"json", "a"}, {"json", "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := lexStructTag(tt.tag)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.tags, tags)
+
+			// every pair is found by reflect.StructTag.Lookup too
+			for _, tag := range tt.tags {
+				_, ok := tt.tag.Lookup(tag.Key)
+				assert.True(t, ok)
+			}
+		})
+	}
+
+	malformed := []struct {
+		name  string
+		tag   reflect.StructTag
+		tags  []Tag
+		error string
+	}{
+		{"missing key", `json:"a" :"b"`, []Tag{{"json", "a"}}, `missing key at offset 9`},
+		{"missing value", `json`, []Tag{}, `key "json" is not followed by a quoted value at offset 0`},
+		{"unquoted value", `json:a`, []Tag{}, `key "json" is not followed by a quoted value at offset 0`},
+		{"unterminated value", `json:"a" env:"b`, []Tag{{"json", "a"}}, `unterminated value of key "env" at offset 13`},
+		{"invalid escape", `json:"\q"`, []Tag{}, `invalid value of key "json" at offset 5`},
+		{"tab separator", "json:\"a\"\tenv:\"b\"", []Tag{{"json", "a"}}, `missing key at offset 8`},
+	}
+
+	for _, tt := range malformed {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := lexStructTag(tt.tag)
+			assert.ErrorIs(t, err, ErrMalformedTag)
+			assert.EqualError(t, err, "malformed tag: "+tt.error)
+			assert.Equal(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestStructTags(t *testing.T) {
+	t.Run("keeps the first value of repeated keys", func(t *testing.T) {
+		tag := reflect.StructTag(`json:"a" json:"b"`)
+
+		tags, err := structTags(tag)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"json": "a"}, tags)
+
+		value, _ := tag.Lookup("json")
+		assert.Equal(t, value, tags["json"])
+	})
+}
+
+func BenchmarkLexStructTag(b *testing.B) {
+	tag := reflect.StructTag(`json:"name,omitempty" env:"name=NAME, default='a,b'" doc:"the user name" validate:"required,min=1"`)
+
+	for b.Loop() {
+		_, _ = lexStructTag(tag)
+	}
+}
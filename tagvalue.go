@@ -0,0 +1,107 @@
+package tiq
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// TagValue is a tag following the Go convention used by `json`, `yaml`, `xml`
+// or `bson` tags, where the first comma-separated segment is the name and the
+// following ones are options (e.g. `json:"name,omitempty"`).
+type TagValue struct {
+	Name    string
+	Options []string
+}
+
+// ParseTagValue parses a tag's content following the Go convention.
+// See TagValue.
+func ParseTagValue(tag string) TagValue {
+	name, rest, found := strings.Cut(tag, ",")
+
+	value := TagValue{Name: strings.TrimSpace(name), Options: []string{}}
+	if !found {
+		return value
+	}
+
+	for option := range strings.SplitSeq(rest, ",") {
+		option = strings.TrimSpace(option)
+		if option != "" {
+			value.Options = append(value.Options, option)
+		}
+	}
+
+	return value
+}
+
+// Has reports whether the given option is set.
+func (t TagValue) Has(option string) bool {
+	return slices.Contains(t.Options, option)
+}
+
+// TagValue returns the given tag parsed following the Go convention, and
+// whether it was found or not. See TagValue.
+func (f *Field) TagValue(name string) (TagValue, bool) {
+	tag, ok := f.Tag(name)
+	if !ok {
+		return TagValue{}, false
+	}
+
+	return ParseTagValue(tag), true
+}
+
+func fnName(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("name() requires exactly 1 argument")
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("name() argument must be a string")
+	}
+
+	return ParseTagValue(str).Name, nil
+}
+
+func fnOption(args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, errors.New("option() requires exactly 2 arguments")
+	}
+
+	if args[0] == nil {
+		return false, nil
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("option() first argument must be a string")
+	}
+
+	option, ok := args[1].(string)
+	if !ok {
+		return nil, errors.New("option() second argument must be a string")
+	}
+
+	return ParseTagValue(str).Has(option), nil
+}
+
+func fnOptions(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("options() requires exactly 1 argument")
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, errors.New("options() argument must be a string")
+	}
+
+	return ParseTagValue(str).Options, nil
+}
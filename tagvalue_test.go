@@ -0,0 +1,145 @@
+package tiq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagValue(t *testing.T) {
+	t.Run("parses name and options", func(t *testing.T) {
+		value := ParseTagValue("name,omitempty,string")
+		assert.Equal(t, TagValue{Name: "name", Options: []string{"omitempty", "string"}}, value)
+	})
+
+	t.Run("parses name without options", func(t *testing.T) {
+		value := ParseTagValue("name")
+		assert.Equal(t, TagValue{Name: "name", Options: []string{}}, value)
+	})
+
+	t.Run("parses options without name", func(t *testing.T) {
+		value := ParseTagValue(",omitempty")
+		assert.Equal(t, TagValue{Name: "", Options: []string{"omitempty"}}, value)
+	})
+
+	t.Run("parses skip marker", func(t *testing.T) {
+		value := ParseTagValue("-")
+		assert.Equal(t, "-", value.Name)
+		assert.Empty(t, value.Options)
+	})
+
+	t.Run("trims whitespace and skips empty options", func(t *testing.T) {
+		value := ParseTagValue(" name , omitempty,, inline ")
+		assert.Equal(t, TagValue{Name: "name", Options: []string{"omitempty", "inline"}}, value)
+	})
+}
+
+func TestTagValue_Has(t *testing.T) {
+	value := ParseTagValue("name,omitempty")
+	assert.True(t, value.Has("omitempty"))
+	assert.False(t, value.Has("name"))
+	assert.False(t, value.Has("string"))
+}
+
+func TestField_TagValue(t *testing.T) {
+	type TestStruct struct {
+		Field1 string `json:"field1,omitempty" yaml:"field_1"`
+	}
+
+	inspector, err := Inspect(TestStruct{})
+	assert.NoError(t, err)
+
+	field, ok := inspector.Field("Field1")
+	assert.True(t, ok)
+
+	t.Run("returns parsed tag value", func(t *testing.T) {
+		value, ok := field.TagValue("json")
+		assert.True(t, ok)
+		assert.Equal(t, "field1", value.Name)
+		assert.True(t, value.Has("omitempty"))
+
+		value, ok = field.TagValue("yaml")
+		assert.True(t, ok)
+		assert.Equal(t, "field_1", value.Name)
+		assert.Empty(t, value.Options)
+	})
+
+	t.Run("returns false when tag does not exist", func(t *testing.T) {
+		value, ok := field.TagValue("xml")
+		assert.False(t, ok)
+		assert.Equal(t, TagValue{}, value)
+	})
+}
+
+func TestFnName(t *testing.T) {
+	t.Run("returns the name segment", func(t *testing.T) {
+		result, err := fnName("field,omitempty")
+		assert.NoError(t, err)
+		assert.Equal(t, "field", result)
+	})
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		result, err := fnName(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("returns error when argument is not string", func(t *testing.T) {
+		_, err := fnName(123)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "argument must be a string")
+	})
+}
+
+func TestFnOption(t *testing.T) {
+	t.Run("returns true when option is set", func(t *testing.T) {
+		result, err := fnOption("field,omitempty", "omitempty")
+		assert.NoError(t, err)
+		assert.True(t, result.(bool))
+	})
+
+	t.Run("does not match the name segment", func(t *testing.T) {
+		result, err := fnOption("omitempty", "omitempty")
+		assert.NoError(t, err)
+		assert.False(t, result.(bool))
+	})
+
+	t.Run("returns false for missing tag", func(t *testing.T) {
+		result, err := fnOption(nil, "omitempty")
+		assert.NoError(t, err)
+		assert.False(t, result.(bool))
+	})
+
+	t.Run("returns error when second argument is not string", func(t *testing.T) {
+		_, err := fnOption("field,omitempty", 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "second argument must be a string")
+	})
+}
+
+func TestFnOptions(t *testing.T) {
+	t.Run("returns every option", func(t *testing.T) {
+		result, err := fnOptions("field,omitempty,string")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"omitempty", "string"}, result)
+	})
+
+	t.Run("returns error when argument is not string", func(t *testing.T) {
+		_, err := fnOptions(123)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseGoConventionTags(t *testing.T) {
+	type JsonSchema struct {
+		Name      string   `tag:"name(json)"`
+		OmitEmpty bool     `tag:"option(json, 'omitempty')"`
+		Options   []string `tag:"json | options()"`
+	}
+
+	schema, err := parseTags[JsonSchema](map[string]string{"json": "user_name,omitempty,string"})
+	assert.NoError(t, err)
+	assert.Equal(t, "user_name", schema.Name)
+	assert.True(t, schema.OmitEmpty)
+	assert.Equal(t, []string{"omitempty", "string"}, schema.Options)
+}
@@ -1,12 +1,15 @@
 package tiq
 
+// Parse evaluates the Schema against the tags of the given field, using a
+// Parser with default options. See NewParser to configure parsing.
 func Parse[Schema any](field *Field) (*Schema, error) {
-	tags, err := field.Tags()
-	if err != nil {
-		return nil, err
-	}
+	return NewParser[Schema]().Parse(field)
+}
 
-	return parseTags[Schema](tags)
+// ParseAll parses every field of the given struct, and returns them along
+// with their schemas. See Parser.ParseAll.
+func ParseAll[Schema any](value any, opts ...Option) ([]Result[Schema], error) {
+	return NewParser[Schema](opts...).ParseAll(value)
 }
 
 func Get(value any, field, tag string) (string, bool) {
@@ -29,10 +32,15 @@ func Set(value any, field string, newValue any) error {
 		return err
 	}
 
-	f, ok := inspector.Field(field)
-	if !ok {
-		return ErrFieldNotFound
+	f, err := inspector.lookup(field, true)
+	if err != nil {
+		return err
+	}
+
+	err = f.Set(newValue)
+	if err != nil {
+		return newFieldError(inspector.value.Type(), field, err)
 	}
 
-	return f.Set(newValue)
+	return nil
 }
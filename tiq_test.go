@@ -61,3 +61,106 @@ func TestSet(t *testing.T) {
 		assert.ErrorIs(t, err, ErrNilValue)
 	})
 }
+
+func TestGetSetPath(t *testing.T) {
+	type Server struct {
+		Host string `json:"host"`
+	}
+	type Config struct {
+		Database struct {
+			Name string `json:"name"`
+		}
+		Servers []Server
+		Labels  map[string]string
+	}
+
+	t.Run("gets tag of nested field", func(t *testing.T) {
+		value, ok := Get(Config{Servers: []Server{{}}}, "Servers[0].Host", "json")
+		assert.True(t, ok)
+		assert.Equal(t, "host", value)
+
+		value, ok = Get(Config{}, "Database.Name", "json")
+		assert.True(t, ok)
+		assert.Equal(t, "name", value)
+	})
+
+	t.Run("sets nested field", func(t *testing.T) {
+		config := Config{Servers: []Server{{}, {}}}
+
+		assert.NoError(t, Set(&config, "Database.Name", "users"))
+		assert.NoError(t, Set(&config, "Servers[1].Host", "localhost"))
+		assert.NoError(t, Set(&config, "Labels[env]", "prod"))
+
+		assert.Equal(t, "users", config.Database.Name)
+		assert.Equal(t, "localhost", config.Servers[1].Host)
+		assert.Equal(t, "prod", config.Labels["env"])
+	})
+
+	t.Run("returns error naming the failing segment", func(t *testing.T) {
+		err := Set(&Config{}, "Servers[0].Host", "localhost")
+		assert.ErrorIs(t, err, ErrFieldNotFound)
+		assert.ErrorContains(t, err, `segment "Servers[0]"`)
+	})
+}
+
+func TestParseAll(t *testing.T) {
+	type EnvSchema struct {
+		Name     string `tag:"env | get('name')"`
+		Optional bool   `tag:"env | has('optional')"`
+	}
+	type Config struct {
+		Url   string `env:"name=URL, optional"`
+		Port  int    `env:"name=PORT"`
+		Debug bool   `json:"debug"`
+	}
+
+	t.Run("returns every field with its schema", func(t *testing.T) {
+		config := Config{}
+
+		results, err := ParseAll[EnvSchema](&config)
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+
+		assert.Equal(t, "Url", results[0].Field.Name)
+		assert.Equal(t, "URL", results[0].Schema.Name)
+		assert.True(t, results[0].Schema.Optional)
+
+		assert.Equal(t, "Port", results[1].Field.Name)
+		assert.Equal(t, "PORT", results[1].Schema.Name)
+
+		assert.Equal(t, "Debug", results[2].Field.Name)
+		assert.Empty(t, results[2].Schema.Name)
+
+		err = results[1].Field.SetFrom("8080")
+		assert.NoError(t, err)
+		assert.Equal(t, 8080, config.Port)
+	})
+
+	t.Run("skips fields without referenced tags", func(t *testing.T) {
+		results, err := ParseAll[EnvSchema](&Config{}, WithSkipUntagged())
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "Url", results[0].Field.Name)
+		assert.Equal(t, "Port", results[1].Field.Name)
+	})
+
+	t.Run("does not count variables as tags", func(t *testing.T) {
+		type PrefixedSchema struct {
+			Name string `tag:"prefix + get(env, 'name')"`
+		}
+		type Config struct {
+			Url    string `env:"name=URL"`
+			Prefix string `prefix:"APP_"`
+		}
+
+		results, err := ParseAll[PrefixedSchema](&Config{}, WithSkipUntagged(), WithVariables(map[string]any{"prefix": "APP_"}))
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, "APP_URL", results[0].Schema.Name)
+	})
+
+	t.Run("returns error when value is not a struct", func(t *testing.T) {
+		_, err := ParseAll[EnvSchema]("config")
+		assert.ErrorIs(t, err, ErrNotAStruct)
+	})
+}
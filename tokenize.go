@@ -0,0 +1,154 @@
+package tiq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// entry is a single entry of a comma-separated key-value list, like `name=URL`
+// or `optional` in "name=URL, optional".
+type entry struct {
+	key   string
+	value string
+}
+
+// content returns the entry's value, or its key if it has no value.
+func (e entry) content() string {
+	if e.value == "" {
+		return e.key
+	}
+
+	return e.value
+}
+
+// tokenize splits a comma-separated key-value list into entries.
+//
+// Keys and values are trimmed, and split on the first `=`. Commas and `=`
+// don't split entries when they are:
+//   - inside a single or double quoted key or value (e.g. `default='a,b'`),
+//     quotes are removed from the result
+//   - inside (), [] or {} brackets (e.g. `regex=^[a-z]{2,5}$`)
+//   - escaped with a backslash (e.g. `sep=\,`)
+//
+// Inside quotes, a backslash escapes the quote character or another backslash.
+// Other backslashes are kept as is, so a regular expression like `^\d+$` is
+// read unchanged. Note that the list is the tag value after Go unquoting, so
+// every backslash is written `\\` inside a struct tag (e.g. `env:"sep=\\,"`).
+func tokenize(list string) ([]entry, error) {
+	entries, unclosed, err := scanList(list, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// unbalanced brackets are most likely part of a value (e.g. `smiley=:(`),
+	// so they are treated as regular characters
+	if unclosed {
+		entries, _, err = scanList(list, false)
+	}
+
+	return entries, err
+}
+
+func scanList(list string, brackets bool) ([]entry, bool, error) {
+	entries := []entry{}
+
+	var (
+		current entry
+		part    []byte
+		keep    int // length of part up to its last significant character
+		inValue bool
+		depth   int
+	)
+
+	flush := func() string {
+		s := string(part[:keep])
+		part, keep = part[:0], 0
+		return s
+	}
+
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+
+		switch {
+		case c == '\\' && i+1 < len(list) && strings.IndexByte(`\,='"`, list[i+1]) != -1:
+			i++
+			part = append(part, list[i])
+			keep = len(part)
+
+		case (c == '\'' || c == '"') && keep == 0:
+			end, content, err := scanQuoted(list, i)
+			if err != nil {
+				return nil, false, err
+			}
+
+			part = append(part, content...)
+			keep = len(part)
+			i = end
+
+		case brackets && strings.IndexByte("([{", c) != -1:
+			depth++
+			part = append(part, c)
+			keep = len(part)
+
+		case brackets && depth > 0 && strings.IndexByte(")]}", c) != -1:
+			depth--
+			part = append(part, c)
+			keep = len(part)
+
+		case c == ',' && depth == 0:
+			if inValue {
+				current.value = flush()
+			} else {
+				current.key = flush()
+			}
+
+			entries = append(entries, current)
+			current, inValue = entry{}, false
+
+		case c == '=' && depth == 0 && !inValue:
+			current.key = flush()
+			inValue = true
+
+		case c == ' ' || c == '\t':
+			if keep > 0 {
+				part = append(part, c)
+			}
+
+		default:
+			part = append(part, c)
+			keep = len(part)
+		}
+	}
+
+	if inValue {
+		current.value = flush()
+	} else {
+		current.key = flush()
+	}
+	entries = append(entries, current)
+
+	return entries, depth > 0, nil
+}
+
+// scanQuoted reads the quoted string starting at list[start], and returns the
+// index of its closing quote along with its unescaped content.
+func scanQuoted(list string, start int) (int, string, error) {
+	quote := list[start]
+	var content []byte
+
+	for i := start + 1; i < len(list); i++ {
+		c := list[i]
+
+		switch {
+		case c == '\\' && i+1 < len(list) && (list[i+1] == quote || list[i+1] == '\\'):
+			i++
+			content = append(content, list[i])
+		case c == quote:
+			return i, string(content), nil
+		default:
+			content = append(content, c)
+		}
+	}
+
+	return 0, "", fmt.Errorf("unterminated quote at offset %d", start)
+}
@@ -0,0 +1,163 @@
+package tiq
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Run("parses key=value pair", func(t *testing.T) {
+		entries, err := tokenize("key=value")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"key", "value"}}, entries)
+	})
+
+	t.Run("parses key without value", func(t *testing.T) {
+		entries, err := tokenize("key")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"key", ""}}, entries)
+	})
+
+	t.Run("trims whitespace", func(t *testing.T) {
+		entries, err := tokenize("  key  =  value  ")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"key", "value"}}, entries)
+	})
+
+	t.Run("handles value with equals sign", func(t *testing.T) {
+		entries, err := tokenize("key=value=extra")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"key", "value=extra"}}, entries)
+	})
+
+	t.Run("splits entries on commas", func(t *testing.T) {
+		entries, err := tokenize("name=URL, optional, oneof=a|b")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"name", "URL"}, {"optional", ""}, {"oneof", "a|b"}}, entries)
+	})
+
+	t.Run("keeps empty entries", func(t *testing.T) {
+		entries, err := tokenize("a,,b")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"a", ""}, {"", ""}, {"b", ""}}, entries)
+
+		entries, err = tokenize("")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"", ""}}, entries)
+	})
+
+	t.Run("keeps inner whitespace", func(t *testing.T) {
+		entries, err := tokenize("doc = the user name ")
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"doc", "the user name"}}, entries)
+	})
+
+	t.Run("does not split quoted values", func(t *testing.T) {
+		entries, err := tokenize(`name=URL, default='a,b,c', sep="=", x`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"name", "URL"}, {"default", "a,b,c"}, {"sep", "="}, {"x", ""}}, entries)
+	})
+
+	t.Run("preserves whitespace inside quotes", func(t *testing.T) {
+		entries, err := tokenize(`prefix=' a b '`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"prefix", " a b "}}, entries)
+	})
+
+	t.Run("supports quoted keys", func(t *testing.T) {
+		entries, err := tokenize(`'a=b'=c`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"a=b", "c"}}, entries)
+	})
+
+	t.Run("unescapes quotes and backslashes inside quotes", func(t *testing.T) {
+		entries, err := tokenize(`msg='it\'s', path="C:\\dir", regex='^\d+$'`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"msg", "it's"}, {"path", `C:\dir`}, {"regex", `^\d+$`}}, entries)
+	})
+
+	t.Run("only treats quotes at the start of a key or value as quoting", func(t *testing.T) {
+		entries, err := tokenize(`name=it's, other=x`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"name", "it's"}, {"other", "x"}}, entries)
+	})
+
+	t.Run("unescapes separators outside quotes", func(t *testing.T) {
+		entries, err := tokenize(`sep=\,, eq=\=, regex=^\d+$`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"sep", ","}, {"eq", "="}, {"regex", `^\d+$`}}, entries)
+	})
+
+	t.Run("does not split inside brackets", func(t *testing.T) {
+		entries, err := tokenize(`regex=^[a-z]{2,5}$, range=(1,10), list=[a,[b,c]], x`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"regex", "^[a-z]{2,5}$"}, {"range", "(1,10)"}, {"list", "[a,[b,c]]"}, {"x", ""}}, entries)
+	})
+
+	t.Run("treats unbalanced brackets as regular characters", func(t *testing.T) {
+		entries, err := tokenize(`smiley=:(, x=1`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"smiley", ":("}, {"x", "1"}}, entries)
+
+		entries, err = tokenize(`frown=):, x=1`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"frown", "):"}, {"x", "1"}}, entries)
+	})
+
+	t.Run("ignores brackets inside quotes", func(t *testing.T) {
+		entries, err := tokenize(`open='(', x=1`)
+		assert.NoError(t, err)
+		assert.Equal(t, []entry{{"open", "("}, {"x", "1"}}, entries)
+	})
+
+	t.Run("returns error for unterminated quotes", func(t *testing.T) {
+		_, err := tokenize(`name='URL, optional`)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unterminated quote at offset 5")
+	})
+}
+
+func TestListFunctionsQuoting(t *testing.T) {
+	const tag = `name=URL, default='a,b,c', validate=regex=^[a-z]{2,5}$, optional`
+
+	t.Run("get() reads quoted values", func(t *testing.T) {
+		result, err := fnGet(tag, "default")
+		assert.NoError(t, err)
+		assert.Equal(t, "a,b,c", result)
+
+		result, err = fnGet(tag, "validate")
+		assert.NoError(t, err)
+		assert.Equal(t, "regex=^[a-z]{2,5}$", result)
+	})
+
+	t.Run("has() ignores keys inside quotes", func(t *testing.T) {
+		result, err := fnHas(tag, "b")
+		assert.NoError(t, err)
+		assert.False(t, result.(bool))
+
+		result, err = fnHas(tag, "optional")
+		assert.NoError(t, err)
+		assert.True(t, result.(bool))
+	})
+
+	t.Run("first(), last() and nth() count quoted entries once", func(t *testing.T) {
+		result, err := fnNth(tag, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "a,b,c", result)
+
+		result, err = fnLast(tag)
+		assert.NoError(t, err)
+		assert.Equal(t, "optional", result)
+
+		result, err = fnFirst(`'x,y', z`)
+		assert.NoError(t, err)
+		assert.Equal(t, "x,y", result)
+	})
+
+	t.Run("return tokenizer errors", func(t *testing.T) {
+		_, err := fnGet(`name='URL`, "name")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "get() unterminated quote")
+	})
+}
@@ -0,0 +1,89 @@
+package tiq
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeInfo holds the metadata of a struct type. It is computed once per type
+// and shared between goroutines, so it must not be modified.
+type typeInfo struct {
+	fields []fieldInfo
+	names  map[string]int // index of every field by name
+}
+
+// fieldInfo holds the metadata of a struct field.
+type fieldInfo struct {
+	field reflect.StructField
+
+	// inner is the struct type Walk descends into, or nil if the field is a leaf.
+	inner reflect.Type
+
+	ordered []Tag
+	tags    map[string]string
+	tagsErr error
+}
+
+// typeInfos caches the metadata of every inspected struct type.
+var typeInfos sync.Map // map[reflect.Type]*typeInfo
+
+// typeInfoOf returns the metadata of the given struct type.
+func typeInfoOf(t reflect.Type) *typeInfo {
+	if info, ok := typeInfos.Load(t); ok {
+		return info.(*typeInfo)
+	}
+
+	info := &typeInfo{
+		fields: make([]fieldInfo, t.NumField()),
+		names:  make(map[string]int, t.NumField()),
+	}
+
+	for i := range info.fields {
+		field := t.Field(i)
+		ordered, err := lexStructTag(field.Tag)
+
+		info.fields[i] = fieldInfo{
+			field:   field,
+			inner:   innerStruct(field),
+			ordered: ordered,
+			tags:    tagMap(ordered),
+			tagsErr: err,
+		}
+		info.names[field.Name] = i
+	}
+
+	actual, _ := typeInfos.LoadOrStore(t, info)
+	return actual.(*typeInfo)
+}
+
+// field returns a Field for the i-th field of the struct value v, whose type
+// is described by info.
+func (info *typeInfo) field(v reflect.Value, i int, prefix string) *Field {
+	meta := &info.fields[i]
+
+	return &Field{
+		Value:       v.Field(i),
+		StructField: meta.field,
+		path:        prefix + meta.field.Name,
+		info:        meta,
+	}
+}
+
+// innerStruct returns the struct type Walk should descend into for the given
+// field, or nil if the field is a leaf.
+func innerStruct(field reflect.StructField) reflect.Type {
+	if !field.IsExported() && !field.Anonymous {
+		return nil
+	}
+
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct || reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return nil
+	}
+
+	return typ
+}
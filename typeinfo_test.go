@@ -0,0 +1,152 @@
+package tiq
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeInfoOf(t *testing.T) {
+	type Inner struct {
+		Host string
+	}
+	type TestStruct struct {
+		Name  string `json:"name" env:"name=NAME"`
+		Inner Inner
+		Ptr   *Inner
+		skip  Inner
+	}
+
+	t.Run("caches metadata per type", func(t *testing.T) {
+		info := typeInfoOf(reflect.TypeFor[TestStruct]())
+		assert.Same(t, info, typeInfoOf(reflect.TypeFor[TestStruct]()))
+
+		cached, ok := typeInfos.Load(reflect.TypeFor[TestStruct]())
+		assert.True(t, ok)
+		assert.Same(t, info, cached)
+	})
+
+	t.Run("holds fields, names, tags and inner structs", func(t *testing.T) {
+		info := typeInfoOf(reflect.TypeFor[TestStruct]())
+
+		assert.Len(t, info.fields, 4)
+		assert.Equal(t, map[string]int{"Name": 0, "Inner": 1, "Ptr": 2, "skip": 3}, info.names)
+		assert.Equal(t, map[string]string{"json": "name", "env": "name=NAME"}, info.fields[0].tags)
+
+		assert.Nil(t, info.fields[0].inner)
+		assert.Equal(t, reflect.TypeFor[Inner](), info.fields[1].inner)
+		assert.Equal(t, reflect.TypeFor[Inner](), info.fields[2].inner)
+		assert.Nil(t, info.fields[3].inner)
+	})
+
+	t.Run("is shared by fields without leaking tags", func(t *testing.T) {
+		inspector, err := Inspect(&TestStruct{})
+		assert.NoError(t, err)
+
+		field, ok := inspector.Field("Name")
+		assert.True(t, ok)
+
+		tags, err := field.Tags()
+		assert.NoError(t, err)
+		tags["json"] = "changed"
+
+		tags, err = field.Tags()
+		assert.NoError(t, err)
+		assert.Equal(t, "name", tags["json"])
+	})
+
+	t.Run("is not used when the field's tag was replaced", func(t *testing.T) {
+		inspector, err := Inspect(&TestStruct{})
+		assert.NoError(t, err)
+
+		field := inspector.Fields()[0]
+		field.StructField.Tag = `yaml:"other"`
+
+		tags, err := field.Tags()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"yaml": "other"}, tags)
+	})
+}
+
+func TestTypeInfoOf_Concurrent(t *testing.T) {
+	type Inner struct {
+		Host string `env:"name=HOST"`
+	}
+	type TestStruct struct {
+		Name  string `env:"name=NAME"`
+		Inner *Inner
+	}
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			for range 100 {
+				value := &TestStruct{}
+				inspector, err := Inspect(value)
+				assert.NoError(t, err)
+
+				for field := range inspector.WalkAll(WithAllocation()) {
+					tags, err := field.Tags()
+					assert.NoError(t, err)
+					assert.Contains(t, tags, "env")
+				}
+
+				host, ok := inspector.Field("Inner.Host")
+				assert.True(t, ok)
+				assert.NoError(t, host.Set("localhost"))
+				assert.Equal(t, "localhost", value.Inner.Host)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+type benchStruct struct {
+	Name     string `json:"name" env:"name=NAME, default='a,b'" doc:"the name"`
+	Port     int    `json:"port" env:"name=PORT, default=8080"`
+	Host     string `json:"host" env:"name=HOST"`
+	Optional bool   `json:"optional,omitempty" env:"name=OPTIONAL, optional"`
+	Database struct {
+		Host string `json:"host" env:"name=DB_HOST"`
+		Port int    `json:"port" env:"name=DB_PORT"`
+	}
+}
+
+func BenchmarkInspect_Tags(b *testing.B) {
+	for b.Loop() {
+		inspector, err := Inspect(&benchStruct{})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for field := range inspector.WalkAll() {
+			_, _ = field.Tags()
+		}
+	}
+}
+
+func BenchmarkInspect_TagsUncached(b *testing.B) {
+	for b.Loop() {
+		inspector, err := Inspect(&benchStruct{})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for field := range inspector.WalkAll() {
+			_, _ = structTags(field.StructField.Tag)
+		}
+	}
+}
+
+func BenchmarkInspector_Lookup(b *testing.B) {
+	inspector, err := Inspect(&benchStruct{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		_, _ = inspector.Lookup("Database.Port")
+	}
+}
@@ -0,0 +1,232 @@
+package tiq
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func fnInt(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("int() requires exactly 1 argument")
+	}
+
+	switch v := args[0].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("int() cannot convert %q to int: %w", v, err)
+		}
+		return int(i), nil
+	case bool:
+		return nil, errors.New("int() cannot convert bool to int")
+	}
+
+	rv := reflect.ValueOf(args[0])
+	switch {
+	case rv.CanInt():
+		return int(rv.Int()), nil
+	case rv.CanUint():
+		if rv.Uint() > math.MaxInt {
+			return nil, fmt.Errorf("int() cannot convert %d to int: value out of range", rv.Uint())
+		}
+		return int(rv.Uint()), nil
+	case rv.CanFloat():
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("int() cannot convert %v to int: not an integer", f)
+		}
+		// float64(math.MaxInt) rounds up to 2^63, which doesn't fit in an int
+		if f < math.MinInt || f >= math.MaxInt {
+			return nil, fmt.Errorf("int() cannot convert %v to int: value out of range", f)
+		}
+		return int(f), nil
+	}
+
+	return nil, fmt.Errorf("int() cannot convert %T to int", args[0])
+}
+
+func fnFloat(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("float() requires exactly 1 argument")
+	}
+
+	switch v := args[0].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("float() cannot convert %q to float: %w", v, err)
+		}
+		return f, nil
+	case bool:
+		return nil, errors.New("float() cannot convert bool to float")
+	}
+
+	rv := reflect.ValueOf(args[0])
+	switch {
+	case rv.CanInt():
+		return float64(rv.Int()), nil
+	case rv.CanUint():
+		return float64(rv.Uint()), nil
+	case rv.CanFloat():
+		return rv.Float(), nil
+	}
+
+	return nil, fmt.Errorf("float() cannot convert %T to float", args[0])
+}
+
+func fnBool(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("bool() requires exactly 1 argument")
+	}
+
+	switch v := args[0].(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("bool() cannot convert %q to bool: %w", v, err)
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("bool() cannot convert %T to bool", args[0])
+}
+
+func fnDuration(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("duration() requires exactly 1 argument")
+	}
+
+	switch v := args[0].(type) {
+	case nil:
+		return nil, nil
+	case time.Duration:
+		return v, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("duration() cannot convert %q to duration: %w", v, err)
+		}
+		return d, nil
+	}
+
+	return nil, fmt.Errorf("duration() cannot convert %T to duration", args[0])
+}
+
+func fnTime(args ...any) (any, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errors.New("time() requires 1 or 2 arguments")
+	}
+
+	layout := time.RFC3339
+	if len(args) == 2 {
+		var ok bool
+		layout, ok = args[1].(string)
+		if !ok {
+			return nil, errors.New("time() second argument must be a string")
+		}
+	}
+
+	switch v := args[0].(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(layout, strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("time() cannot convert %q to time: %w", v, err)
+		}
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("time() cannot convert %T to time", args[0])
+}
+
+// byteUnits maps lower-cased size units to their number of bytes.
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+func fnBytes(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, errors.New("bytes() requires exactly 1 argument")
+	}
+
+	if args[0] == nil {
+		return nil, nil
+	}
+
+	str, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("bytes() cannot convert %T to bytes", args[0])
+	}
+
+	size, err := parseBytes(str)
+	if err != nil {
+		return nil, fmt.Errorf("bytes() cannot convert %q to bytes: %w", str, err)
+	}
+
+	return size, nil
+}
+
+// parseBytes parses a size with an optional decimal (kB, MB...) or binary
+// (KiB, MiB...) unit, like "10MiB" or "1.5 GB", into a number of bytes.
+func parseBytes(str string) (int64, error) {
+	str = strings.TrimSpace(str)
+
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(str)
+	}
+
+	number, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", number)
+	}
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", str[i:])
+	}
+
+	size := n * multiplier
+	// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit in an int64
+	if size >= math.MaxInt64 {
+		return 0, errors.New("value out of range")
+	}
+
+	return int64(size), nil
+}
@@ -0,0 +1,136 @@
+package tiq
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionFunctions(t *testing.T) {
+	tests := []struct {
+		name   string
+		fn     func(args ...any) (any, error)
+		args   []any
+		result any
+	}{
+		{"int from string", fnInt, []any{" 42 "}, 42},
+		{"int from zero-padded string", fnInt, []any{"010"}, 10},
+		{"int from int64", fnInt, []any{int64(3)}, 3},
+		{"int from integral float", fnInt, []any{2.0}, 2},
+		{"float from string", fnFloat, []any{"1.5"}, 1.5},
+		{"float from int", fnFloat, []any{2}, 2.0},
+		{"bool from string", fnBool, []any{"true"}, true},
+		{"bool from bool", fnBool, []any{false}, false},
+		{"duration from string", fnDuration, []any{"1m30s"}, 90 * time.Second},
+		{"time with default layout", fnTime, []any{"2024-01-02T03:04:05Z"}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"time with layout", fnTime, []any{"2024-01-02", time.DateOnly}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"bytes without unit", fnBytes, []any{"512"}, int64(512)},
+		{"bytes with decimal unit", fnBytes, []any{"10MB"}, int64(10_000_000)},
+		{"bytes with binary unit", fnBytes, []any{"10MiB"}, int64(10 << 20)},
+		{"bytes with spaced lower-case unit", fnBytes, []any{"1.5 kib"}, int64(1536)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.fn(tt.args...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.result, result)
+		})
+	}
+
+	t.Run("returns nil for missing tag", func(t *testing.T) {
+		for _, fn := range []func(args ...any) (any, error){fnInt, fnFloat, fnBool, fnDuration, fnTime, fnBytes} {
+			result, err := fn(nil)
+			assert.NoError(t, err)
+			assert.Nil(t, result)
+		}
+	})
+
+	t.Run("returns conversion errors", func(t *testing.T) {
+		tests := []struct {
+			fn    func(args ...any) (any, error)
+			args  []any
+			error string
+		}{
+			{fnInt, []any{"abc"}, `int() cannot convert "abc" to int`},
+			{fnInt, []any{1.5}, "int() cannot convert 1.5 to int: not an integer"},
+			{fnInt, []any{true}, "int() cannot convert bool to int"},
+			{fnInt, []any{"0x10"}, `int() cannot convert "0x10" to int`},
+			{fnInt, []any{1e19}, "int() cannot convert 1e+19 to int: value out of range"},
+			{fnInt, []any{float64(math.MaxInt)}, "int() cannot convert 9.223372036854776e+18 to int: value out of range"},
+			{fnFloat, []any{"abc"}, `float() cannot convert "abc" to float`},
+			{fnBool, []any{"yes"}, `bool() cannot convert "yes" to bool`},
+			{fnBool, []any{1}, "bool() cannot convert int to bool"},
+			{fnDuration, []any{"10"}, `duration() cannot convert "10" to duration`},
+			{fnTime, []any{"2024-01-02"}, `time() cannot convert "2024-01-02" to time`},
+			{fnTime, []any{"2024-01-02", 1}, "time() second argument must be a string"},
+			{fnBytes, []any{"10XB"}, `bytes() cannot convert "10XB" to bytes: unknown unit "XB"`},
+			{fnBytes, []any{"MiB"}, `bytes() cannot convert "MiB" to bytes: invalid number ""`},
+			{fnBytes, []any{10}, "bytes() cannot convert int to bytes"},
+			{fnBytes, []any{"8192PiB"}, `bytes() cannot convert "8192PiB" to bytes: value out of range`},
+			{fnInt, []any{}, "int() requires exactly 1 argument"},
+		}
+
+		for _, tt := range tests {
+			_, err := tt.fn(tt.args...)
+			assert.ErrorContains(t, err, tt.error)
+		}
+	})
+}
+
+func TestConversionFunctions_Expressions(t *testing.T) {
+	type Schema struct {
+		Large   bool          `tag:"env | get('max') | int() > 10"`
+		Max     int           `tag:"int(get(env, 'max')) * 2"`
+		Ratio   float64       `tag:"env | get('ratio') | float()"`
+		Enabled bool          `tag:"env | get('enabled') | bool()"`
+		Timeout time.Duration `tag:"env | get('timeout') | duration()"`
+		Since   time.Time     `tag:"env | get('since') | time('2006-01-02')"`
+		Size    int64         `tag:"env | get('size') | bytes()"`
+	}
+
+	t.Run("converts values inside expressions", func(t *testing.T) {
+		schema, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{
+			"env": "max=20, ratio=0.5, enabled=true, timeout=5s, since=2024-01-02, size=1GiB",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &Schema{
+			Large:   true,
+			Max:     40,
+			Ratio:   0.5,
+			Enabled: true,
+			Timeout: 5 * time.Second,
+			Since:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Size:    1 << 30,
+		}, schema)
+	})
+
+	t.Run("returns conversion errors in strict mode", func(t *testing.T) {
+		type Schema struct {
+			Max int `tag:"env | get('max') | int()"`
+		}
+
+		_, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{"env": "max=abc"})
+		assert.ErrorIs(t, err, ErrEvalTag)
+		assert.ErrorContains(t, err, `int() cannot convert "abc" to int`)
+	})
+	t.Run("requires a default to compare missing values", func(t *testing.T) {
+		type Schema struct {
+			Large bool `tag:"env | get('max') | int() > 10"`
+		}
+
+		_, err := NewParser[Schema](WithStrict()).ParseTags(map[string]string{"env": ""})
+		assert.ErrorIs(t, err, ErrEvalTag)
+		assert.ErrorContains(t, err, "invalid operation: <nil> > int")
+
+		type DefaultSchema struct {
+			Large bool `tag:"(env | get('max') | int() | default(0)) > 10"`
+		}
+
+		schema, err := NewParser[DefaultSchema](WithStrict()).ParseTags(map[string]string{"env": ""})
+		assert.NoError(t, err)
+		assert.False(t, schema.Large)
+	})
+}
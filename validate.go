@@ -0,0 +1,78 @@
+package tiq
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ValidateSchema compiles every expression of the schema, and checks that
+// their output types match their field's types. Every problem is reported at
+// once as Errors, which makes it suited to be called from init() or a test.
+//
+// Type checks are stricter than Parse, which converts values when it can (e.g.
+// Parse turns `true` into []string{"true"}, while ValidateSchema reports a bool
+// expression on a []string field). Expressions with an unknown output type,
+// such as `default()`, are not checked.
+func ValidateSchema[Schema any](opts ...Option) error {
+	c := newConfig(opts...)
+	c.aggregate = true
+	c.typeCheck = true
+
+	_, err := newPlan(reflect.TypeFor[Schema](), c)
+	return err
+}
+
+// checkType reports whether an expression of the given output type can be
+// assigned to a field of the given type.
+func checkType(output, field reflect.Type) error {
+	if !compatible(output, field) {
+		return fmt.Errorf("%w: expression returns %s, field is %s", ErrTagType, output, field)
+	}
+
+	return nil
+}
+
+func compatible(output, field reflect.Type) bool {
+	if output == nil || output.Kind() == reflect.Interface {
+		return true
+	}
+
+	for field.Kind() == reflect.Pointer {
+		field = field.Elem()
+	}
+	for output.Kind() == reflect.Pointer {
+		output = output.Elem()
+	}
+
+	if output.AssignableTo(field) {
+		return true
+	}
+
+	switch field.Kind() {
+	case reflect.Interface:
+		return output.Implements(field)
+	case reflect.Struct:
+		return output.Kind() == reflect.Map
+	case reflect.Map:
+		return output.Kind() == reflect.Map || output == reflect.TypeFor[[]Entry]()
+	case reflect.Slice, reflect.Array:
+		if output.Kind() != reflect.Slice && output.Kind() != reflect.Array {
+			return false
+		}
+		return compatible(output.Elem(), field.Elem())
+	}
+
+	return isScalar(output) && isScalar(field)
+}
+
+func isScalar(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package tiq
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchema(t *testing.T) {
+	t.Run("accepts valid schemas", func(t *testing.T) {
+		type Range struct {
+			Min, Max int
+		}
+		type Schema struct {
+			Name     string            `tag:"env | get('name') | default(screamingSnake($field.name))"`
+			Optional bool              `tag:"env | has('optional')"`
+			Oneof    []string          `tag:"env | get('oneof') | split('|')"`
+			Max      *int              `tag:"env | get('max') | int()"`
+			Timeout  time.Duration     `tag:"env | get('timeout') | duration()"`
+			Since    time.Time         `tag:"env | get('since') | time()"`
+			Range    Range             `tag:"{'min': env | get('min')}"`
+			Options  map[string]string `tag:"entries(env)"`
+			Raw      any               `tag:"env"`
+			Port     int               `tag:"env | get('port')" tagdefault:"8080"`
+		}
+
+		assert.NoError(t, ValidateSchema[Schema]())
+	})
+
+	t.Run("reports every compile and type error", func(t *testing.T) {
+		type Schema struct {
+			Name   string   `tag:"env | get('name')"`
+			Flags  []string `tag:"env | has('optional')"`
+			Broken string   `tag:"env |"`
+			Label  string   `tag:"env | get('oneof') | split('|')"`
+			Range  struct{} `tag:"1 + 2"`
+			Port   int      `tag:"env | get('port')" tagdefault:"abc"`
+		}
+
+		err := ValidateSchema[Schema]()
+
+		var errs Errors
+		assert.ErrorAs(t, err, &errs)
+		assert.Len(t, errs, 5)
+		assert.ErrorIs(t, err, ErrTagType)
+		assert.ErrorIs(t, err, ErrCompileTag)
+		assert.ErrorIs(t, err, ErrCannotConvert)
+
+		var fieldErr *FieldError
+		assert.ErrorAs(t, errs[0], &fieldErr)
+		assert.Equal(t, "Flags", fieldErr.FieldPath)
+		assert.Equal(t, "tag", fieldErr.TagKey)
+		assert.Equal(t, "env | has('optional')", fieldErr.Expression)
+		assert.EqualError(t, fieldErr, "tiq.Schema.Flags: mismatched tag type: expression returns bool, field is []string")
+	})
+
+	t.Run("uses parser options", func(t *testing.T) {
+		type Schema struct {
+			Name string `expr:"env | shout()"`
+		}
+
+		fns := NewFunctions()
+		err := fns.Register("shout", fnShout, new(func(string) ([]string, error)))
+		assert.NoError(t, err)
+
+		err = ValidateSchema[Schema](WithTagName("expr"), WithFunctions(fns))
+		assert.ErrorIs(t, err, ErrTagType)
+	})
+
+	t.Run("does not change how Parse converts values", func(t *testing.T) {
+		type Schema struct {
+			Flags []string `tag:"env | has('optional')"`
+		}
+
+		schema, err := NewParser[Schema]().ParseTags(map[string]string{"env": "optional"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"true"}, schema.Flags)
+	})
+
+	t.Run("returns error when schema is not a struct", func(t *testing.T) {
+		assert.ErrorIs(t, ValidateSchema[string](), ErrNotAStruct)
+	})
+}
+
+func TestCheckType(t *testing.T) {
+	tests := []struct {
+		output reflect.Type
+		field  reflect.Type
+		ok     bool
+	}{
+		{nil, reflect.TypeFor[int](), true},
+		{reflect.TypeFor[any](), reflect.TypeFor[[]string](), true},
+		{reflect.TypeFor[string](), reflect.TypeFor[int](), true},
+		{reflect.TypeFor[bool](), reflect.TypeFor[string](), true},
+		{reflect.TypeFor[int](), reflect.TypeFor[*int](), true},
+		{reflect.TypeFor[[]any](), reflect.TypeFor[[]int](), true},
+		{reflect.TypeFor[[]string](), reflect.TypeFor[[]int](), true},
+		{reflect.TypeFor[map[string]any](), reflect.TypeFor[struct{ A int }](), true},
+		{reflect.TypeFor[[]Entry](), reflect.TypeFor[map[string]string](), true},
+		{reflect.TypeFor[bool](), reflect.TypeFor[[]string](), false},
+		{reflect.TypeFor[[]string](), reflect.TypeFor[string](), false},
+		{reflect.TypeFor[[]bool](), reflect.TypeFor[[][]string](), false},
+		{reflect.TypeFor[string](), reflect.TypeFor[map[string]string](), false},
+		{reflect.TypeFor[int](), reflect.TypeFor[time.Time](), false},
+		{reflect.TypeFor[string](), reflect.TypeFor[error](), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v to %v", tt.output, tt.field), func(t *testing.T) {
+			err := checkType(tt.output, tt.field)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrTagType)
+			}
+		})
+	}
+}
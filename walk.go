@@ -0,0 +1,99 @@
+package tiq
+
+import (
+	"encoding"
+	"iter"
+	"reflect"
+)
+
+// WalkOption configures how Inspector.Walk traverses a struct.
+type WalkOption func(*walkConfig)
+
+type walkConfig struct {
+	allocate bool
+}
+
+// WithAllocation makes Walk allocate nil pointers to structs so their fields
+// can be visited. Without it, nil pointers are returned as leaf fields.
+func WithAllocation() WalkOption {
+	return func(c *walkConfig) {
+		c.allocate = true
+	}
+}
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// Walk returns every leaf field of the struct, recursively descending into
+// nested structs, pointers to structs and anonymous embedded fields.
+// Structs implementing encoding.TextUnmarshaler (e.g. time.Time) are considered leaves.
+func (i *Inspector) Walk(opts ...WalkOption) []*Field {
+	fields := []*Field{}
+	for field := range i.WalkAll(opts...) {
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// WalkAll is like Walk, but returns an iterator lazily traversing the struct.
+func (i *Inspector) WalkAll(opts ...WalkOption) iter.Seq[*Field] {
+	config := &walkConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(yield func(*Field) bool) {
+		walk(config, i.value, "", map[reflect.Type]bool{i.value.Type(): true}, yield)
+	}
+}
+
+// walk yields every leaf field of v, and reports whether the walk should continue.
+func walk(config *walkConfig, v reflect.Value, prefix string, visiting map[reflect.Type]bool, yield func(*Field) bool) bool {
+	info := typeInfoOf(v.Type())
+
+	for i := range info.fields {
+		field := info.field(v, i, prefix)
+
+		inner, ok := descend(config, field, visiting)
+		if !ok {
+			if !yield(field) {
+				return false
+			}
+			continue
+		}
+
+		visiting[inner.Type()] = true
+		more := walk(config, inner, field.path+".", visiting, yield)
+		delete(visiting, inner.Type())
+
+		if !more {
+			return false
+		}
+	}
+
+	return true
+}
+
+// descend returns the struct value to walk into for the given field, or false
+// if the field should be treated as a leaf.
+func descend(config *walkConfig, field *Field, visiting map[reflect.Type]bool) (reflect.Value, bool) {
+	typ := field.info.inner
+	if typ == nil || visiting[typ] {
+		return reflect.Value{}, false
+	}
+
+	v := field.Value
+	if v.Kind() != reflect.Pointer {
+		return v, true
+	}
+
+	if v.IsNil() {
+		if !config.allocate || !v.CanSet() {
+			return reflect.Value{}, false
+		}
+
+		v.Set(reflect.New(typ))
+	}
+
+	return v.Elem(), true
+}
@@ -0,0 +1,179 @@
+package tiq
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func paths(fields []*Field) []string {
+	result := []string{}
+	for _, f := range fields {
+		result = append(result, f.Path())
+	}
+	return result
+}
+
+func TestInspector_Walk(t *testing.T) {
+	type PoolConfig struct {
+		MaxConns int `env:"name=MAX_CONNS"`
+	}
+	type DBConfig struct {
+		Host string `env:"name=DB_HOST"`
+		Pool PoolConfig
+	}
+	type BaseConfig struct {
+		Debug bool `env:"name=DEBUG"`
+	}
+
+	t.Run("descends into nested structs", func(t *testing.T) {
+		type Config struct {
+			Name     string
+			Database DBConfig
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		fields := inspector.Walk()
+		assert.Equal(t, []string{"Name", "Database.Host", "Database.Pool.MaxConns"}, paths(fields))
+
+		tag, ok := fields[2].Tag("env")
+		assert.True(t, ok)
+		assert.Equal(t, "name=MAX_CONNS", tag)
+	})
+
+	t.Run("descends into embedded structs", func(t *testing.T) {
+		type Config struct {
+			BaseConfig
+			Name string
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"BaseConfig.Debug", "Name"}, paths(inspector.Walk()))
+	})
+
+	t.Run("descends into non-nil pointers to structs", func(t *testing.T) {
+		type Config struct {
+			Database *DBConfig
+		}
+
+		config := Config{Database: &DBConfig{}}
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		fields := inspector.Walk()
+		assert.Equal(t, []string{"Database.Host", "Database.Pool.MaxConns"}, paths(fields))
+
+		err = fields[0].Set("localhost")
+		assert.NoError(t, err)
+		assert.Equal(t, "localhost", config.Database.Host)
+	})
+
+	t.Run("returns nil pointers as leaves by default", func(t *testing.T) {
+		type Config struct {
+			Database *DBConfig
+		}
+
+		config := Config{}
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"Database"}, paths(inspector.Walk()))
+		assert.Nil(t, config.Database)
+	})
+
+	t.Run("allocates nil pointers when asked", func(t *testing.T) {
+		type Config struct {
+			Database *DBConfig
+		}
+
+		config := Config{}
+		inspector, err := Inspect(&config)
+		assert.NoError(t, err)
+
+		fields := inspector.Walk(WithAllocation())
+		assert.Equal(t, []string{"Database.Host", "Database.Pool.MaxConns"}, paths(fields))
+		assert.NotNil(t, config.Database)
+
+		err = fields[1].Set(10)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, config.Database.Pool.MaxConns)
+	})
+
+	t.Run("does not allocate when struct is not addressable", func(t *testing.T) {
+		type Config struct {
+			Database *DBConfig
+		}
+
+		inspector, err := Inspect(Config{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"Database"}, paths(inspector.Walk(WithAllocation())))
+	})
+
+	t.Run("treats text unmarshalers and unexported structs as leaves", func(t *testing.T) {
+		type Config struct {
+			CreatedAt time.Time
+			internal  DBConfig
+		}
+
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"CreatedAt", "internal"}, paths(inspector.Walk()))
+	})
+
+	t.Run("stops on recursive types", func(t *testing.T) {
+		type Node struct {
+			Value int
+			Next  *Node
+		}
+
+		inspector, err := Inspect(&Node{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"Value", "Next"}, paths(inspector.Walk(WithAllocation())))
+	})
+}
+
+func TestInspector_WalkAll(t *testing.T) {
+	type Inner struct {
+		A, B string
+	}
+	type Config struct {
+		First  Inner
+		Second Inner
+	}
+
+	t.Run("yields leaf fields lazily", func(t *testing.T) {
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		visited := []string{}
+		for field := range inspector.WalkAll() {
+			visited = append(visited, field.Path())
+			if field.Path() == "First.B" {
+				break
+			}
+		}
+		assert.Equal(t, []string{"First.A", "First.B"}, visited)
+	})
+
+	t.Run("can be iterated several times", func(t *testing.T) {
+		inspector, err := Inspect(&Config{})
+		assert.NoError(t, err)
+
+		seq := inspector.WalkAll()
+		for range 2 {
+			count := 0
+			for range seq {
+				count++
+			}
+			assert.Equal(t, 4, count)
+		}
+	})
+}